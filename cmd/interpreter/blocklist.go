@@ -0,0 +1,47 @@
+package main
+
+import "strings"
+
+// removeBlocked removes the blocklisted phrases from the extracted text and blocks, dropping the blocks left empty.
+// The returned boolean is true when the text was made of blocklisted phrases only.
+func (a *App) removeBlocked(text string, blocks []block) (string, []block, bool) {
+	remove := func(s string) string {
+		for _, pattern := range a.blocklist {
+			s = pattern.ReplaceAllString(s, "")
+		}
+		return strings.TrimSpace(s)
+	}
+
+	kept := blocks[:0]
+	for _, b := range blocks {
+		if b.text = remove(b.text); b.text != "" {
+			kept = append(kept, b)
+		}
+	}
+	remaining := remove(text)
+	return remaining, kept, text != "" && remaining == ""
+}
+
+// allowed keeps the blocks matching the allowlist. The returned boolean is false when the text doesn't match it,
+// or none of the blocks does.
+func (a *App) allowed(text string, blocks []block) ([]block, bool) {
+	matches := func(s string) bool {
+		for _, pattern := range a.allowlist {
+			if pattern.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if blocks == nil {
+		return nil, matches(text)
+	}
+	kept := blocks[:0]
+	for _, b := range blocks {
+		if matches(b.text) {
+			kept = append(kept, b)
+		}
+	}
+	return kept, len(kept) > 0
+}
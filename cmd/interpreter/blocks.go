@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+)
+
+// block is a piece of text detected on screen along with its location in the screenshot.
+type block struct {
+	text    string
+	bounds  image.Rectangle
+	speaker int
+}
+
+func filterBlocksByConfidence(annotation *visionpb.TextAnnotation, threshold configuration.ConfidenceThreshold, lineHyphens bool) []block {
+	var blocks []block
+	for _, page := range annotation.Pages {
+		for _, b := range page.Blocks {
+			var buffer bytes.Buffer
+			for _, paragraph := range b.Paragraphs {
+				for _, word := range paragraph.Words {
+					language := detectedLanguage(word.Property, paragraph.Property, b.Property, page.Property)
+					if word.Confidence < threshold.For(language) {
+						continue
+					}
+					for _, s := range word.Symbols {
+						buffer.WriteString(symbolText(s, lineHyphens))
+					}
+				}
+			}
+			if buffer.Len() == 0 {
+				continue
+			}
+			blocks = append(blocks, block{text: buffer.String(), bounds: boundingRectangle(b.BoundingBox)})
+		}
+	}
+	return blocks
+}
+
+// symbolText returns the text of a symbol. With lineHyphens, a hyphen ending a line is followed by a line break
+// for cleanup.Dehyphenate to rejoin the word it splits.
+func symbolText(s *visionpb.Symbol, lineHyphens bool) string {
+	if !lineHyphens || s.Text != "-" || s.Property == nil || s.Property.DetectedBreak == nil {
+		return s.Text
+	}
+	switch s.Property.DetectedBreak.Type {
+	case visionpb.TextAnnotation_DetectedBreak_EOL_SURE_SPACE, visionpb.TextAnnotation_DetectedBreak_LINE_BREAK:
+		return s.Text + "\n"
+	default:
+		return s.Text
+	}
+}
+
+// removeSmallBlocks drops the blocks covering less than minRegion of their page area, such as counters or icons.
+func removeSmallBlocks(annotation *visionpb.TextAnnotation, minRegion float64) {
+	for _, page := range annotation.Pages {
+		pageArea := float64(page.Width) * float64(page.Height)
+		if pageArea == 0 {
+			continue
+		}
+		blocks := page.Blocks[:0]
+		for _, b := range page.Blocks {
+			bounds := boundingRectangle(b.BoundingBox)
+			if float64(bounds.Dx()*bounds.Dy())/pageArea < minRegion {
+				continue
+			}
+			blocks = append(blocks, b)
+		}
+		page.Blocks = blocks
+	}
+}
+
+func boundingRectangle(poly *visionpb.BoundingPoly) image.Rectangle {
+	var r image.Rectangle
+	if poly == nil {
+		return r
+	}
+	for i, v := range poly.Vertices {
+		p := image.Rect(int(v.X), int(v.Y), int(v.X)+1, int(v.Y)+1)
+		if i == 0 {
+			r = p
+			continue
+		}
+		r = r.Union(p)
+	}
+	return r
+}
+
+// sortByReadingOrder sorts blocks top-to-bottom, then left-to-right.
+func sortByReadingOrder(blocks []block) {
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if blocks[i].bounds.Min.Y != blocks[j].bounds.Min.Y {
+			return blocks[i].bounds.Min.Y < blocks[j].bounds.Min.Y
+		}
+		return blocks[i].bounds.Min.X < blocks[j].bounds.Min.X
+	})
+}
+
+func joinBlocks(blocks []block) string {
+	texts := make([]string, len(blocks))
+	for i, b := range blocks {
+		texts[i] = b.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// numberLines prefixes each line with its position in the list.
+func numberLines(lines []string) string {
+	var buffer bytes.Buffer
+	for i, line := range lines {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString(fmt.Sprintf("%d. %s", i+1, line))
+	}
+	return buffer.String()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+)
+
+const (
+	confidenceMargin   = 4
+	confidenceFontSize = 20
+)
+
+// scoredWord is a word found by OCR along with its confidence and the threshold it is filtered with.
+type scoredWord struct {
+	text       string
+	confidence float32
+	threshold  float32
+}
+
+// confidenceOverlay holds the words of the last annotation, one slice per paragraph, for Draw.
+type confidenceOverlay struct {
+	mu         sync.Mutex
+	face       font.Face
+	paragraphs [][]scoredWord
+}
+
+func (c *confidenceOverlay) set(annotation *visionpb.TextAnnotation, threshold configuration.ConfidenceThreshold) {
+	var paragraphs [][]scoredWord
+	if annotation != nil {
+		for _, page := range annotation.Pages {
+			for _, block := range page.Blocks {
+				for _, paragraph := range block.Paragraphs {
+					words := make([]scoredWord, 0, len(paragraph.Words))
+					for _, word := range paragraph.Words {
+						var text string
+						for _, s := range word.Symbols {
+							text += s.Text
+						}
+						language := detectedLanguage(word.Property, paragraph.Property, block.Property, page.Property)
+						words = append(words, scoredWord{text, word.Confidence, threshold.For(language)})
+					}
+					paragraphs = append(paragraphs, words)
+				}
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paragraphs = paragraphs
+}
+
+// confidenceColor is red for the words filtered out, then goes from yellow at the threshold to green at full confidence.
+func confidenceColor(word scoredWord) color.RGBA {
+	if word.confidence < word.threshold {
+		return color.RGBA{R: 0xFF, G: 0x30, B: 0x30, A: 0xFF}
+	}
+	ratio := float32(1)
+	if word.threshold < 1 {
+		ratio = (word.confidence - word.threshold) / (1 - word.threshold)
+	}
+	return color.RGBA{R: uint8(0xFF * (1 - ratio)), G: 0xFF, B: 0x30, A: 0xFF}
+}
+
+// draw renders the color-coded words at the bottom of the window, wrapping them to its width.
+func (c *confidenceOverlay) draw(screen *ebiten.Image, width, height int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.paragraphs) == 0 {
+		return
+	}
+
+	type placedWord struct {
+		scoredWord
+		x, line int
+	}
+	space := font.MeasureString(c.face, " ").Round()
+	var placed []placedWord
+	line := 0
+	for _, paragraph := range c.paragraphs {
+		x := confidenceMargin
+		for _, word := range paragraph {
+			advance := font.MeasureString(c.face, word.text).Round()
+			if x > confidenceMargin && x+advance > width-confidenceMargin {
+				x, line = confidenceMargin, line+1
+			}
+			placed = append(placed, placedWord{word, x, line})
+			x += advance + space
+		}
+		line++
+	}
+
+	lineHeight := c.face.Metrics().Height.Round()
+	top := height - line*lineHeight - confidenceMargin
+	ebitenutil.DrawRect(screen, 0, float64(top-confidenceMargin), float64(width), float64(line*lineHeight+2*confidenceMargin), color.RGBA{A: 0xC0})
+	for _, word := range placed {
+		text.Draw(screen, word.text, c.face, word.x, top+word.line*lineHeight+c.face.Metrics().Ascent.Round(), confidenceColor(word.scoredWord))
+	}
+}
@@ -1,40 +1,308 @@
 package configuration
 
 import (
+	"bytes"
 	_ "embed"
+	"errors"
 	"fmt"
+	"image"
 	"image/color"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bquenin/interpreter/internal/cleanup"
+	"github.com/bquenin/interpreter/internal/ocr"
 	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/mitchellh/mapstructure"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
 const (
 	ConfigName = "config"
+
+	// translatorAPIEnv allows running without configuration file when set, along with authenticationKeyEnv for DeepL
+	// or endpointEnv for LibreTranslate, and windowTitleEnv to capture a window
+	translatorAPIEnv     = "INTERPRETER_TRANSLATOR_API"
+	authenticationKeyEnv = "INTERPRETER_TRANSLATOR_AUTHENTICATION_KEY"
+	endpointEnv          = "INTERPRETER_TRANSLATOR_ENDPOINT"
+	windowTitleEnv       = "INTERPRETER_WINDOW_TITLE"
+)
+
+// Subtitles display modes
+const (
+	ModeSubtitles = "subtitles"
+	ModeChoices   = "choices"
 )
 
 //go:embed default.yml
 var defaultConfiguration []byte
 
 type Translator struct {
-	To                string `mapstructure:"to"`
-	API               string `mapstructure:"api"`
-	AuthenticationKey string `mapstructure:"authentication-key"`
+	To                []string     `mapstructure:"to"`
+	API               string       `mapstructure:"api"`
+	AuthenticationKey string       `mapstructure:"authentication-key"`
+	Endpoint          string       `mapstructure:"endpoint"`
+	Keys              []string     `mapstructure:"keys"`
+	MaxRetries        int          `mapstructure:"max-retries"`
+	TagHandling       string       `mapstructure:"tag-handling"`
+	Compare           *Translator  `mapstructure:"compare"`
+	Fallback          []Translator `mapstructure:"fallback"`
+	Timeout           string       `mapstructure:"timeout"`
+	Paragraphs        bool         `mapstructure:"paragraphs"`
+	SkipTrivial       float64      `mapstructure:"skip-trivial"`
+	CacheSize         int          `mapstructure:"cache-size"`
+	Cycle             []string     `mapstructure:"cycle"`
+}
+
+// AutoTimeout is the translator timeout value deriving the timeout from the refresh rate.
+const AutoTimeout = "auto"
+
+// IsAutoTimeout reports whether the translator timeout is derived from the refresh rate
+func (t *Translator) IsAutoTimeout() bool {
+	return t.Timeout == AutoTimeout
+}
+
+// GetTimeout returns how long a translation may take as duration, 0 meaning no limit or an automatic timeout
+func (t *Translator) GetTimeout() time.Duration {
+	if t.Timeout == "" || t.IsAutoTimeout() {
+		return 0
+	}
+	timeout, err := time.ParseDuration(t.Timeout)
+	if err != nil {
+		log.Panic().Msgf("unable to parse translator timeout: %s. Please check your configuration.", t.Timeout)
+	}
+	return timeout
+}
+
+type Zone struct {
+	X      int `mapstructure:"x"`
+	Y      int `mapstructure:"y"`
+	Width  int `mapstructure:"width"`
+	Height int `mapstructure:"height"`
+}
+
+type OCR struct {
+	MaxImageSize    int      `mapstructure:"max-image-size"`
+	StripCJKSpaces  bool     `mapstructure:"strip-cjk-spaces"`
+	MinRegion       float64  `mapstructure:"min-region"`
+	ScrollStitch    bool     `mapstructure:"scroll-stitch"`
+	ScrollMaxLength int      `mapstructure:"scroll-max-length"`
+	Incremental     bool     `mapstructure:"incremental"`
+	AutoLanguage    bool     `mapstructure:"auto-language"`
+	LockLanguage    int      `mapstructure:"lock-source-language"`
+	Exclude         []Zone   `mapstructure:"exclude"`
+	ScriptSwitching bool     `mapstructure:"script-switching"`
+	DiffTranslation bool     `mapstructure:"diff-translation"`
+	DiffMinPrefix   int      `mapstructure:"diff-min-prefix"`
+	ImageFormat     string   `mapstructure:"image-format"`
+	JPEGSubsampling string   `mapstructure:"jpeg-subsampling"`
+	Dehyphenate     bool     `mapstructure:"dehyphenate"`
+	EmptyTolerance  int      `mapstructure:"empty-tolerance"`
+	Scale           float64  `mapstructure:"scale"`
+	LatencyTarget   string   `mapstructure:"latency-target"`
+	PageSeparator   string   `mapstructure:"page-separator"`
+	SplitPages      bool     `mapstructure:"split-pages"`
+	MinScale        float64  `mapstructure:"min-scale"`
+	LanguageHints   []string `mapstructure:"language-hints"`
+}
+
+// GetLanguageHints returns the languages of the text to help OCR with, none letting it detect the language.
+func (o *OCR) GetLanguageHints() ([]string, error) {
+	for _, hint := range o.LanguageHints {
+		if !languageCode.MatchString(hint) {
+			return nil, fmt.Errorf("invalid `ocr.language-hints` value: %q is not a language code", hint)
+		}
+	}
+	return o.LanguageHints, nil
+}
+
+// GetPageSeparator returns what the pages Vision splits tall images into are joined with. Split pages being translated
+// separately, they need a separator.
+func (o *OCR) GetPageSeparator() (string, error) {
+	if o.SplitPages && o.PageSeparator == "" {
+		return "", fmt.Errorf("invalid `ocr.page-separator` value: it can't be empty with `ocr.split-pages`")
+	}
+	return o.PageSeparator, nil
+}
+
+// GetLatencyTarget returns the OCR latency the image scale is adjusted to, 0 disabling it.
+func (o *OCR) GetLatencyTarget() time.Duration {
+	target, err := time.ParseDuration(o.LatencyTarget)
+	if err != nil {
+		log.Panic().Msgf("unable to parse OCR latency target: %s. Please check your configuration.", o.LatencyTarget)
+	}
+	return target
+}
+
+// GetMinScale returns the lowest factor the screenshots are resized by to meet the OCR latency target.
+func (o *OCR) GetMinScale() (float64, error) {
+	if o.MinScale <= 0 || o.MinScale > o.Scale {
+		return 0, fmt.Errorf("invalid `ocr.min-scale` value: %v must be greater than 0 and at most `ocr.scale`", o.MinScale)
+	}
+	return o.MinScale, nil
+}
+
+// GetLockSourceLanguage returns how many times in a row a language is detected before it's used as the hint for the
+// rest of the session, auto-language locking the first one. 0 disables it.
+func (o *OCR) GetLockSourceLanguage() int {
+	if o.LockLanguage <= 0 && o.AutoLanguage {
+		return 1
+	}
+	return o.LockLanguage
+}
+
+// GetScale returns the factor the screenshots are resized by before OCR.
+func (o *OCR) GetScale() (float64, error) {
+	if o.Scale <= 0 || o.Scale > 1 {
+		return 0, fmt.Errorf("invalid `ocr.scale` value: %v must be greater than 0 and at most 1", o.Scale)
+	}
+	return o.Scale, nil
+}
+
+// GetImageFormat returns the format of the image sent to Vision, defaulting to JPEG.
+func (o *OCR) GetImageFormat() (string, error) {
+	switch strings.ToLower(o.ImageFormat) {
+	case "", ocr.FormatJPEG, "jpg":
+		return ocr.FormatJPEG, nil
+	case ocr.FormatPNG:
+		return ocr.FormatPNG, nil
+	default:
+		return "", fmt.Errorf("invalid `ocr.image-format` value: %s", o.ImageFormat)
+	}
+}
+
+// GetJPEGSubsampling returns the chroma subsampling of the JPEG images sent to Vision, defaulting to 4:2:0.
+func (o *OCR) GetJPEGSubsampling() (string, error) {
+	switch o.JPEGSubsampling {
+	case "", ocr.Subsampling420:
+		return ocr.Subsampling420, nil
+	case ocr.Subsampling444:
+		return ocr.Subsampling444, nil
+	default:
+		return "", fmt.Errorf("invalid `ocr.jpeg-subsampling` value: %s", o.JPEGSubsampling)
+	}
+}
+
+// GetExcludedZones returns the zones of the screenshot hidden from OCR.
+func (o *OCR) GetExcludedZones() []image.Rectangle {
+	zones := make([]image.Rectangle, 0, len(o.Exclude))
+	for _, zone := range o.Exclude {
+		zones = append(zones, image.Rect(zone.X, zone.Y, zone.X+zone.Width, zone.Y+zone.Height))
+	}
+	return zones
 }
 
 type Subs struct {
-	Font       Font       `mapstructure:"font"`
-	Background Background `mapstructure:"background"`
+	Font              Font       `mapstructure:"font"`
+	Background        Background `mapstructure:"background"`
+	Persist           bool       `mapstructure:"persist"`
+	SceneCutThreshold float64    `mapstructure:"scene-cut-threshold"`
+	Mode              string     `mapstructure:"mode"`
+	MaxWidth          float64    `mapstructure:"max-width"`
+	DedupThreshold    float64    `mapstructure:"dedup-threshold"`
+	StatusLine        bool       `mapstructure:"status-line"`
+	StatusCorner      string     `mapstructure:"status-corner"`
+	ShowConfidence    bool       `mapstructure:"show-confidence"`
+	StaleIndicator    string     `mapstructure:"stale-indicator"`
+	LiveFile          string     `mapstructure:"live-file"`
+	GroupByColor      bool       `mapstructure:"group-by-color"`
+	ClickToSelect     bool       `mapstructure:"click-to-select"`
+	Style             string     `mapstructure:"style"`
+	TickerSpeed       float64    `mapstructure:"ticker-speed"`
+	Layout            string     `mapstructure:"layout"`
+	PositionSmoothing string     `mapstructure:"position-smoothing"`
+}
+
+// GetStaleIndicator returns how old the subtitles get before being marked as outdated when new text waits for its translation, 0 disabling it
+func (s *Subs) GetStaleIndicator() time.Duration {
+	threshold, err := time.ParseDuration(s.StaleIndicator)
+	if err != nil {
+		log.Panic().Msgf("unable to parse stale indicator: %s. Please check your configuration.", s.StaleIndicator)
+	}
+	return threshold
+}
+
+// GetPositionSmoothing returns how long the translations take to follow their block when it moves in positional layout, 0 disabling it
+func (s *Subs) GetPositionSmoothing() time.Duration {
+	smoothing, err := time.ParseDuration(s.PositionSmoothing)
+	if err != nil {
+		log.Panic().Msgf("unable to parse position smoothing: %s. Please check your configuration.", s.PositionSmoothing)
+	}
+	return smoothing
 }
 
 type Font struct {
-	Color string `mapstructure:"color"`
-	Size  int    `mapstructure:"size"`
+	Color             string                  `mapstructure:"color"`
+	Size              int                     `mapstructure:"size"`
+	Gradient          []string                `mapstructure:"gradient"`
+	GradientDirection string                  `mapstructure:"gradient-direction"`
+	Languages         map[string]LanguageFont `mapstructure:"languages"`
+}
+
+// LanguageFont overrides the subtitles font for a target language.
+type LanguageFont struct {
+	File string `mapstructure:"file"`
+	Size int    `mapstructure:"size"`
+}
+
+// GetSize returns the font size of the language, the default one when not set.
+func (l *LanguageFont) GetSize(language string, defaultSize int) int {
+	if l.Size == 0 {
+		return defaultSize
+	}
+	return clampFontSize(fmt.Sprintf("subs.font.languages.%s.size", language), l.Size)
+}
+
+// Language returns the language of the font override matching the target language, its base language matching too,
+// such as "pt" for "pt-BR".
+func (f *Font) Language(target string) (string, bool) {
+	target = strings.ToLower(target)
+	if _, ok := f.Languages[target]; ok {
+		return target, true
+	}
+	base, _, _ := strings.Cut(target, "-")
+	_, ok := f.Languages[base]
+	return base, ok
+}
+
+// Gradient directions
+const (
+	GradientVertical   = "vertical"
+	GradientHorizontal = "horizontal"
+)
+
+// Font sizes
+const (
+	DefaultFontSize = 24
+	MinFontSize     = 8
+	MaxFontSize     = 200
+)
+
+// GetSize returns the font size, clamped to a usable range. A size that isn't positive falls back to the default one.
+func (f *Font) GetSize() int {
+	return clampFontSize("subs.font.size", f.Size)
+}
+
+func clampFontSize(key string, size int) int {
+	switch {
+	case size <= 0:
+		log.Warn().Msgf("invalid `%s` value: %d, using %d instead", key, size, DefaultFontSize)
+		return DefaultFontSize
+	case size < MinFontSize:
+		log.Warn().Msgf("`%s` value %d is too small, using %d instead", key, size, MinFontSize)
+		return MinFontSize
+	case size > MaxFontSize:
+		log.Warn().Msgf("`%s` value %d is too large, using %d instead", key, size, MaxFontSize)
+		return MaxFontSize
+	default:
+		return size
+	}
 }
 
 type Background struct {
@@ -42,13 +310,162 @@ type Background struct {
 	Opacity int    `mapstructure:"opacity"`
 }
 
+// Window corners
+const (
+	CornerTopLeft     = "top-left"
+	CornerTopRight    = "top-right"
+	CornerBottomLeft  = "bottom-left"
+	CornerBottomRight = "bottom-right"
+)
+
+// Click-through modifiers
+const (
+	ModifierShift   = "shift"
+	ModifierControl = "control"
+	ModifierAlt     = "alt"
+)
+
+type Display struct {
+	Monitor              int    `mapstructure:"monitor"`
+	ClickThroughModifier string `mapstructure:"click-through-modifier"`
+	Transparent          bool   `mapstructure:"transparent"`
+	Floating             bool   `mapstructure:"floating"`
+	VSync                bool   `mapstructure:"vsync"`
+	Decorated            bool   `mapstructure:"decorated"`
+	AlwaysOnTop          bool   `mapstructure:"always-on-top"`
+	PauseWhileMoving     bool   `mapstructure:"pause-while-moving"`
+}
+
+// Capture backends
+const (
+	CaptureWindow       = "window"
+	CaptureScreenRegion = "screen-region"
+	CaptureChildWindow  = "child-window"
+)
+
+type Capture struct {
+	Backend     string `mapstructure:"backend"`
+	Region      Zone   `mapstructure:"region"`
+	ChildClass  string `mapstructure:"child-class"`
+	SaveOnError bool   `mapstructure:"save-on-error"`
+}
+
+// GetBackend returns how the text to translate is captured, defaulting to the window.
+func (c *Capture) GetBackend() (string, error) {
+	switch c.Backend {
+	case "", CaptureWindow:
+		return CaptureWindow, nil
+	case CaptureScreenRegion:
+		if c.Region.Width <= 0 || c.Region.Height <= 0 {
+			return "", fmt.Errorf("invalid `capture.region` value: width and height must be positive")
+		}
+		return CaptureScreenRegion, nil
+	case CaptureChildWindow:
+		return CaptureChildWindow, nil
+	default:
+		return "", fmt.Errorf("invalid `capture.backend` value: %s", c.Backend)
+	}
+}
+
+// GetRegion returns the zone of the screen captured by the screen-region backend.
+func (c *Capture) GetRegion() image.Rectangle {
+	return image.Rect(c.Region.X, c.Region.Y, c.Region.X+c.Region.Width, c.Region.Y+c.Region.Height)
+}
+
+type Keys struct {
+	CopyTranslation string `mapstructure:"copy-translation"`
+	CopySource      string `mapstructure:"copy-source"`
+	ResetLanguage   string `mapstructure:"reset-language"`
+	Pause           string `mapstructure:"pause"`
+	CycleLanguage   string `mapstructure:"cycle-language"`
+}
+
+// Pipeline error policies
+const (
+	OnErrorSkip  = "skip"
+	OnErrorRetry = "retry"
+	OnErrorFatal = "fatal"
+)
+
+type OnError struct {
+	Policy         string `mapstructure:"policy"`
+	Retries        int    `mapstructure:"retries"`
+	ReconnectAfter int    `mapstructure:"reconnect-after"`
+	UseCache       bool   `mapstructure:"use-cache"`
+}
+
+// ConfidenceThreshold holds the OCR confidence threshold per detected language.
+// The DefaultLanguage key applies to the languages without a threshold of their own.
+type ConfidenceThreshold map[string]float32
+
+const DefaultLanguage = "default"
+
+// For returns the confidence threshold of the given language, e.g. "ja" or "en-US".
+func (t ConfidenceThreshold) For(languageCode string) float32 {
+	if threshold, ok := t[languageCode]; ok {
+		return threshold
+	}
+	if base, _, found := strings.Cut(languageCode, "-"); found {
+		if threshold, ok := t[base]; ok {
+			return threshold
+		}
+	}
+	return t[DefaultLanguage]
+}
+
+// confidenceThresholdHook allows the confidence threshold to be a single number applying to all languages.
+func confidenceThresholdHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(ConfidenceThreshold{}) {
+		return data, nil
+	}
+	switch from.Kind() {
+	case reflect.Float32, reflect.Float64, reflect.Int, reflect.Int64, reflect.String:
+		threshold, err := strconv.ParseFloat(fmt.Sprint(data), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid `confidence-threshold` value: %w", err)
+		}
+		return ConfidenceThreshold{DefaultLanguage: float32(threshold)}, nil
+	default:
+		return data, nil
+	}
+}
+
+// Debug holds the debug mode settings.
+type Debug struct {
+	Enabled             bool `mapstructure:"enabled"`
+	AnnotateScreenshots bool `mapstructure:"annotate-screenshots"`
+}
+
+// debugHook allows debug to be a single boolean enabling the debug mode, as it used to be.
+func debugHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(Debug{}) || from.Kind() != reflect.Bool {
+		return data, nil
+	}
+	return Debug{Enabled: data.(bool)}, nil
+}
+
 type Configuration struct {
-	WindowTitle         string     `mapstructure:"window-title"`
-	RefreshRate         string     `mapstructure:"refresh-rate"`
-	ConfidenceThreshold float32    `mapstructure:"confidence-threshold"`
-	Translator          Translator `mapstructure:"translator"`
-	Subs                Subs       `mapstructure:"subs"`
-	Debug               bool
+	WindowTitle         string              `mapstructure:"window-title"`
+	RefreshRate         string              `mapstructure:"refresh-rate"`
+	RefreshRateMin      string              `mapstructure:"refresh-rate-min"`
+	RefreshRateMax      string              `mapstructure:"refresh-rate-max"`
+	FrameCacheTTL       string              `mapstructure:"frame-cache-ttl"`
+	FocusGracePeriod    string              `mapstructure:"focus-grace-period"`
+	StartupDelay        string              `mapstructure:"startup-delay"`
+	ConfidenceThreshold ConfidenceThreshold `mapstructure:"confidence-threshold"`
+	Capture             Capture             `mapstructure:"capture"`
+	OCR                 OCR                 `mapstructure:"ocr"`
+	Translator          Translator          `mapstructure:"translator"`
+	Subs                Subs                `mapstructure:"subs"`
+	Display             Display             `mapstructure:"display"`
+	OnError             OnError             `mapstructure:"on-error"`
+	Keys                Keys                `mapstructure:"keys"`
+	Normalize           string              `mapstructure:"normalize"`
+	ExportOnExit        string              `mapstructure:"export-on-exit"`
+	Blocklist           []string            `mapstructure:"blocklist"`
+	Allowlist           []string            `mapstructure:"allowlist"`
+	TranscriptFile      string              `mapstructure:"transcript-file"`
+	Debug               Debug               `mapstructure:"debug"`
 }
 
 func Read() (*Configuration, error) {
@@ -68,31 +485,150 @@ func Read() (*Configuration, error) {
 	viper.AddConfigPath("$HOME")
 	viper.SetConfigType("yml")
 	viper.SetConfigName(ConfigName)
+	viper.SetDefault("refresh-rate-min", "1s")
+	viper.SetDefault("refresh-rate-max", "10s")
+	viper.SetDefault("frame-cache-ttl", "0s")
+	viper.SetDefault("focus-grace-period", "0s")
+	viper.SetDefault("startup-delay", "0s")
+	viper.SetDefault("capture.backend", CaptureWindow)
+	viper.SetDefault("subs.font.size", DefaultFontSize)
+	viper.SetDefault("subs.style", StyleBox)
+	viper.SetDefault("subs.layout", LayoutBox)
+	viper.SetDefault("display.pause-while-moving", true)
+	viper.SetDefault("subs.ticker-speed", 120)
+	viper.SetDefault("ocr.max-image-size", 8_000_000)
+	viper.SetDefault("ocr.image-format", ocr.FormatJPEG)
+	viper.SetDefault("ocr.jpeg-subsampling", ocr.Subsampling420)
+	viper.SetDefault("ocr.scale", 1.0)
+	viper.SetDefault("ocr.latency-target", "0s")
+	viper.SetDefault("ocr.page-separator", "\n")
+	viper.SetDefault("ocr.min-scale", 0.5)
+	viper.SetDefault("ocr.scroll-max-length", 2000)
+	viper.SetDefault("ocr.diff-min-prefix", 20)
+	viper.SetDefault("ocr.empty-tolerance", 1)
+	viper.SetDefault("translator.max-retries", 3)
+	viper.SetDefault("translator.timeout", "10s")
+	viper.SetDefault("subs.max-width", 1.0)
+	viper.SetDefault("subs.status-corner", CornerBottomRight)
+	viper.SetDefault("subs.stale-indicator", "0s")
+	viper.SetDefault("subs.position-smoothing", "0s")
+	viper.SetDefault("display.monitor", -1)
+	viper.SetDefault("display.transparent", true)
+	viper.SetDefault("display.floating", true)
+	viper.SetDefault("display.vsync", true)
+	viper.SetDefault("display.decorated", true)
+	viper.SetDefault("on-error.policy", OnErrorSkip)
+	viper.SetDefault("on-error.retries", 2)
+	viper.SetDefault("on-error.reconnect-after", 5)
+	viper.SetDefault("normalize", cleanup.NormalizeNone)
+	viper.SetDefault("keys.copy-translation", "C")
+	viper.SetDefault("keys.copy-source", "S")
+	viper.SetDefault("keys.reset-language", "L")
+	viper.SetDefault("keys.pause", "P")
+	viper.SetDefault("keys.cycle-language", "N")
 	if err := viper.ReadInConfig(); err != nil {
-		return nil, err
+		var configNotFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &configNotFound) || !fromEnvironment() {
+			return nil, err
+		}
+		// Without configuration file, a translator set from the environment runs on the default configuration
+		if err := viper.ReadConfig(bytes.NewReader(defaultConfiguration)); err != nil {
+			return nil, err
+		}
 	}
 
 	// Unmarshal config
 	var config Configuration
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := viper.Unmarshal(&config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		confidenceThresholdHook,
+		debugHook,
+	))); err != nil {
 		return nil, err
 	}
 
 	return &config, nil
 }
 
-func WriteDefault() error {
+// fromEnvironment reports whether the environment variables are enough to run without configuration file: a
+// translator, along with its authentication key for DeepL or its endpoint for LibreTranslate.
+func fromEnvironment() bool {
+	switch os.Getenv(translatorAPIEnv) {
+	case "":
+		return false
+	case "deepl":
+		return os.Getenv(authenticationKeyEnv) != ""
+	case "libretranslate":
+		return os.Getenv(endpointEnv) != ""
+	default:
+		return true
+	}
+}
+
+// WriteDefault writes the default configuration file next to the executable and returns its path.
+func WriteDefault() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	configFilePath := filepath.Join(filepath.Dir(executable), ConfigName+".yml")
+	return configFilePath, os.WriteFile(configFilePath, defaultConfiguration, 0644)
+}
+
+// WriteSetup writes the default configuration file with the window title and translator settings replaced.
+func WriteSetup(c *Configuration) error {
 	executable, err := os.Executable()
 	if err != nil {
 		return err
 	}
 
+	config := string(defaultConfiguration)
+	for pattern, value := range map[string]string{
+		`(?m)^window-title: ".*?"`:         "window-title: " + strconv.Quote(c.WindowTitle),
+		`(?m)^  api: ".*?"`:                "  api: " + strconv.Quote(c.Translator.API),
+		`(?m)^  to: ".*?"`:                 "  to: " + strconv.Quote(c.Translator.Target()),
+		`(?m)^  authentication-key: ".*?"`: "  authentication-key: " + strconv.Quote(c.Translator.AuthenticationKey),
+		`(?m)^  endpoint: ".*?"`:           "  endpoint: " + strconv.Quote(c.Translator.Endpoint),
+	} {
+		config = regexp.MustCompile(pattern).ReplaceAllLiteralString(config, value)
+	}
+
 	configFilePath := filepath.Join(filepath.Dir(executable), ConfigName+".yml")
-	return os.WriteFile(configFilePath, defaultConfiguration, 0644)
+	return os.WriteFile(configFilePath, []byte(config), 0644)
+}
+
+// AutoRefreshRate is the refresh rate value tuning the refresh rate to how often the text changes.
+const AutoRefreshRate = "auto"
+
+// IsAutoRefreshRate reports whether the refresh rate is tuned automatically
+func (c *Configuration) IsAutoRefreshRate() bool {
+	return c.RefreshRate == AutoRefreshRate
+}
+
+// GetRefreshRateBounds returns the minimum and maximum automatic refresh rates as durations
+func (c *Configuration) GetRefreshRateBounds() (time.Duration, time.Duration) {
+	min, err := time.ParseDuration(c.RefreshRateMin)
+	if err != nil {
+		log.Panic().Msgf("unable to parse minimum refresh rate: %s. Please check your configuration.", c.RefreshRateMin)
+	}
+	max, err := time.ParseDuration(c.RefreshRateMax)
+	if err != nil {
+		log.Panic().Msgf("unable to parse maximum refresh rate: %s. Please check your configuration.", c.RefreshRateMax)
+	}
+	if min > max {
+		log.Panic().Msgf("minimum refresh rate %s is greater than maximum refresh rate %s. Please check your configuration.", min, max)
+	}
+	return min, max
 }
 
 // GetRefreshRate returns the refresh rate as duration
 func (c *Configuration) GetRefreshRate() time.Duration {
+	if c.IsAutoRefreshRate() {
+		min, _ := c.GetRefreshRateBounds()
+		return min
+	}
 	refreshRate, err := time.ParseDuration(c.RefreshRate)
 	if err != nil {
 		log.Panic().Msgf("unable to parse refresh rate: %s. Please check your configuration.", c.RefreshRate)
@@ -100,16 +636,193 @@ func (c *Configuration) GetRefreshRate() time.Duration {
 	return refreshRate
 }
 
+// GetFrameCacheTTL returns how long a captured frame is reused as duration
+func (c *Configuration) GetFrameCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(c.FrameCacheTTL)
+	if err != nil {
+		log.Panic().Msgf("unable to parse frame cache ttl: %s. Please check your configuration.", c.FrameCacheTTL)
+	}
+	return ttl
+}
+
+// GetStartupDelay returns how long to wait before the first capture as duration
+func (c *Configuration) GetStartupDelay() time.Duration {
+	delay, err := time.ParseDuration(c.StartupDelay)
+	if err != nil {
+		log.Panic().Msgf("unable to parse startup delay: %s. Please check your configuration.", c.StartupDelay)
+	}
+	return delay
+}
+
+// GetFocusGracePeriod returns how long the subtitles are kept after the captured window loses focus as duration
+func (c *Configuration) GetFocusGracePeriod() time.Duration {
+	gracePeriod, err := time.ParseDuration(c.FocusGracePeriod)
+	if err != nil {
+		log.Panic().Msgf("unable to parse focus grace period: %s. Please check your configuration.", c.FocusGracePeriod)
+	}
+	return gracePeriod
+}
+
+// GetTranslator returns the translator, remembering the last translations when a cache size is set.
+// The google translator is recreated after consecutive failures, inside the cache not to lose it when reconnecting.
 func (c *Configuration) GetTranslator() (translate.Translator, error) {
+	translator, err := c.reconnecting(c.Translator.newTranslator)
+	if err != nil {
+		return nil, err
+	}
+	return c.Translator.cached(translator)
+}
+
+// reconnecting recreates the google translator after `on-error.reconnect-after` consecutive failures, its connection
+// going stale in long sessions.
+func (c *Configuration) reconnecting(connect func() (translate.Translator, error)) (translate.Translator, error) {
+	if c.Translator.API != "google" || c.OnError.ReconnectAfter <= 0 {
+		return connect()
+	}
+	reconnecting, err := translate.NewReconnecting(connect, c.OnError.ReconnectAfter)
+	if err != nil {
+		return nil, err
+	}
+	return reconnecting, nil
+}
+
+// GetCycleTranslators returns a translator for each target language the cycle-language key switches to, reconnected
+// and cached as the main one.
+func (c *Configuration) GetCycleTranslators() ([]translate.Translator, error) {
+	translators := make([]translate.Translator, 0, len(c.Translator.Cycle))
+	for _, target := range c.Translator.Cycle {
+		t := c.Translator
+		t.To, t.Fallback = []string{target}, nil
+		translator, err := c.reconnecting(t.newTranslator)
+		if err != nil {
+			translate.NewSwitch(translators...).Close()
+			return nil, fmt.Errorf("invalid `translator.cycle` value: %w", err)
+		}
+		if translator, err = t.cached(translator); err != nil {
+			translate.NewSwitch(translators...).Close()
+			return nil, err
+		}
+		translators = append(translators, translator)
+	}
+	return translators, nil
+}
+
+// cached remembers the last translations of the translator when a cache size is set.
+func (t *Translator) cached(translator translate.Translator) (translate.Translator, error) {
+	if t.CacheSize == 0 {
+		return translator, nil
+	}
+	cached, err := translate.NewCached(translator, t.CacheSize)
+	if err != nil {
+		translator.Close()
+		return nil, fmt.Errorf("invalid `translator.cache-size` value: %w", err)
+	}
+	return cached, nil
+}
+
+// GetCompareTranslator returns the translator whose translations are displayed alongside the main one, or nil if none is configured.
+func (c *Configuration) GetCompareTranslator() (translate.Translator, error) {
+	if c.Translator.Compare == nil || c.Translator.Compare.API == "" {
+		return nil, nil
+	}
+	translator, err := c.Translator.Compare.newTranslator()
+	if err != nil {
+		return nil, fmt.Errorf("invalid `translator.compare` value: %w", err)
+	}
+	return translator, nil
+}
+
+// ForegroundWindow is the window title capturing whichever window is focused.
+const ForegroundWindow = ":foreground"
+
+// placeholderWindowTitle is the window title of the default configuration, to be replaced before capturing a window.
+const placeholderWindowTitle = "change me"
+
+// GetWindowTitle returns the title of the window to capture, failing when it is still the one of the default
+// configuration, such as when running from the environment without window title.
+func (c *Configuration) GetWindowTitle() (string, error) {
+	if c.WindowTitle == "" || c.WindowTitle == placeholderWindowTitle {
+		return "", fmt.Errorf("invalid `window-title` value: %q, please set the title of the window to capture in the configuration file or with %s", c.WindowTitle, windowTitleEnv)
+	}
+	return c.WindowTitle, nil
+}
+
+// keyCooldownsFile holds the cooldowns of the exhausted keys across sessions, next to the configuration file.
+const keyCooldownsFile = "key-cooldowns.json"
+
+// languageCode matches the target languages of the translators, such as "en", "pt-BR" or "zh-Hant".
+var languageCode = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,4})?$`)
+
+// newTranslator returns the translator, trying the fallback translators in order when it fails.
+func (t *Translator) newTranslator() (translate.Translator, error) {
+	translator, err := t.newProvider()
+	if err != nil || len(t.Fallback) == 0 {
+		return translator, err
+	}
+	translators := []translate.Translator{translator}
+	for i := range t.Fallback {
+		fallback, err := t.Fallback[i].newProvider()
+		if err != nil {
+			translate.NewFallback(translators...).Close()
+			return nil, fmt.Errorf("invalid `translator.fallback[%d]` value: %w", i, err)
+		}
+		translators = append(translators, fallback)
+	}
+	return translate.NewFallback(translators...), nil
+}
+
+// Target returns the preferred target language, the first one of the list.
+func (t *Translator) Target() string {
+	if len(t.To) == 0 {
+		return ""
+	}
+	return t.To[0]
+}
+
+// newProvider returns the translator of the api, trying its target languages in order when several are listed.
+func (t *Translator) newProvider() (translate.Translator, error) {
+	if t.API == "none" {
+		return translate.NewNone(), nil
+	}
+	if len(t.To) == 0 {
+		return nil, errors.New("no target language")
+	}
+	translators := make([]translate.Translator, 0, len(t.To))
+	for _, target := range t.To {
+		translator, err := t.newDialect(target)
+		if err != nil {
+			translate.NewDialects(t.To, translators).Close()
+			return nil, err
+		}
+		translators = append(translators, translator)
+	}
+	if len(translators) == 1 {
+		return translators[0], nil
+	}
+	return translate.NewDialects(t.To, translators), nil
+}
+
+func (t *Translator) newDialect(target string) (translate.Translator, error) {
+	if !languageCode.MatchString(target) {
+		return nil, fmt.Errorf("invalid target language: %q", target)
+	}
 	var translator translate.Translator
 	var err error
-	switch c.Translator.API {
+	switch t.API {
 	case "google":
-		translator, err = translate.NewGoogle(c.Translator.To)
+		translator, err = translate.NewGoogle(target)
 	case "deepl":
-		translator, err = translate.NewDeepL(c.Translator.To, c.Translator.AuthenticationKey)
+		if len(t.Keys) > 0 {
+			translator, err = translate.NewKeyRotation(t.Keys, func(key string) (translate.Translator, error) {
+				return translate.NewDeepL(target, key, t.MaxRetries, t.TagHandling)
+			}, filepath.Join(filepath.Dir(viper.ConfigFileUsed()), keyCooldownsFile))
+			break
+		}
+		translator, err = translate.NewDeepL(target, t.AuthenticationKey, t.MaxRetries, t.TagHandling)
+	case "libretranslate":
+		translator, err = translate.NewLibreTranslate(target, t.Endpoint, t.AuthenticationKey)
 	default:
-		log.Fatal().Msgf("unsupported translator api: %s", c.Translator.API)
+		return nil, fmt.Errorf("unsupported translator api: %s", t.API)
 	}
 	if err != nil {
 		return nil, err
@@ -137,6 +850,38 @@ func (f *Font) GetColor() (color.RGBA, error) {
 	return color, nil
 }
 
+// GetGradient returns the two colors the subtitles text goes from and to, or nil when it has a single color.
+func (f *Font) GetGradient() ([]color.RGBA, error) {
+	if len(f.Gradient) == 0 {
+		return nil, nil
+	}
+	if len(f.Gradient) != 2 {
+		return nil, fmt.Errorf("invalid `subs.font.gradient` value: expected 2 colors, got %d", len(f.Gradient))
+	}
+	gradient := make([]color.RGBA, 0, 2)
+	for _, s := range f.Gradient {
+		c, err := parseColorString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid `subs.font.gradient` value: %w", err)
+		}
+		c.A = 0xFF
+		gradient = append(gradient, c)
+	}
+	return gradient, nil
+}
+
+// GetGradientDirection returns the direction of the subtitles text gradient, defaulting to vertical.
+func (f *Font) GetGradientDirection() (string, error) {
+	switch f.GradientDirection {
+	case "", GradientVertical:
+		return GradientVertical, nil
+	case GradientHorizontal:
+		return GradientHorizontal, nil
+	default:
+		return "", fmt.Errorf("invalid `subs.font.gradient-direction` value: %s", f.GradientDirection)
+	}
+}
+
 func (b *Background) GetColor() (color.RGBA, error) {
 	color, err := parseColorString(b.Color)
 	if err != nil {
@@ -145,3 +890,153 @@ func (b *Background) GetColor() (color.RGBA, error) {
 	color.A = uint8(b.Opacity)
 	return color, nil
 }
+
+// GetMode returns the subtitles display mode, defaulting to subtitles.
+func (s *Subs) GetMode() (string, error) {
+	switch s.Mode {
+	case "", ModeSubtitles:
+		return ModeSubtitles, nil
+	case ModeChoices:
+		return ModeChoices, nil
+	default:
+		return "", fmt.Errorf("invalid `subs.mode` value: %s", s.Mode)
+	}
+}
+
+// Subtitles styles
+const (
+	StyleBox    = "box"
+	StyleTicker = "ticker"
+)
+
+// GetStyle returns how the subtitles are rendered, defaulting to a box at the top of the window.
+func (s *Subs) GetStyle() (string, error) {
+	switch s.Style {
+	case "", StyleBox:
+		return StyleBox, nil
+	case StyleTicker:
+		if s.TickerSpeed <= 0 {
+			return "", fmt.Errorf("invalid `subs.ticker-speed` value: %v must be greater than 0", s.TickerSpeed)
+		}
+		return StyleTicker, nil
+	default:
+		return "", fmt.Errorf("invalid `subs.style` value: %s", s.Style)
+	}
+}
+
+// Subtitles layouts
+const (
+	LayoutBox        = "box"
+	LayoutPositional = "positional"
+)
+
+// GetLayout returns where the subtitles are drawn: in a single box at the top of the window, or each block's
+// translation over the block.
+func (s *Subs) GetLayout() (string, error) {
+	switch s.Layout {
+	case "", LayoutBox:
+		return LayoutBox, nil
+	case LayoutPositional:
+		return LayoutPositional, nil
+	default:
+		return "", fmt.Errorf("invalid `subs.layout` value: %s", s.Layout)
+	}
+}
+
+// GetMaxWidth returns the maximum width of the subtitles as a fraction of the window width.
+func (s *Subs) GetMaxWidth() (float64, error) {
+	if s.MaxWidth <= 0 || s.MaxWidth > 1 {
+		return 0, fmt.Errorf("invalid `subs.max-width` value: %v must be greater than 0 and at most 1", s.MaxWidth)
+	}
+	return s.MaxWidth, nil
+}
+
+// GetStatusCorner returns the corner of the window where the status line is drawn.
+func (s *Subs) GetStatusCorner() (string, error) {
+	switch s.StatusCorner {
+	case CornerTopLeft, CornerTopRight, CornerBottomLeft, CornerBottomRight:
+		return s.StatusCorner, nil
+	default:
+		return "", fmt.Errorf("invalid `subs.status-corner` value: %s", s.StatusCorner)
+	}
+}
+
+// GetClickThroughModifier returns the modifier key making the window interactive, or an empty string if the window is never click-through.
+func (d *Display) GetClickThroughModifier() (string, error) {
+	switch d.ClickThroughModifier {
+	case "", ModifierShift, ModifierControl, ModifierAlt:
+		return d.ClickThroughModifier, nil
+	default:
+		return "", fmt.Errorf("invalid `display.click-through-modifier` value: %s", d.ClickThroughModifier)
+	}
+}
+
+// GetPolicy returns what to do when capturing, extracting or translating text fails.
+func (o *OnError) GetPolicy() (string, error) {
+	switch o.Policy {
+	case OnErrorSkip, OnErrorRetry, OnErrorFatal:
+		return o.Policy, nil
+	default:
+		return "", fmt.Errorf("invalid `on-error.policy` value: %s", o.Policy)
+	}
+}
+
+// GetBlocklist returns the patterns of the phrases never translated.
+// Patterns between slashes, such as "/(?i)demo version/", are regular expressions, the others are literal text.
+func (c *Configuration) GetBlocklist() ([]*regexp.Regexp, error) {
+	return compilePhrases("blocklist", c.Blocklist)
+}
+
+// GetAllowlist returns the patterns the extracted text must match to be translated.
+// Patterns between slashes, such as "/[.!?]$/", are regular expressions, the others are literal text.
+func (c *Configuration) GetAllowlist() ([]*regexp.Regexp, error) {
+	return compilePhrases("allowlist", c.Allowlist)
+}
+
+// compilePhrases compiles the phrases of the list, literal text unless written between slashes.
+func compilePhrases(key string, phrases []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(phrases))
+	for _, phrase := range phrases {
+		expression := regexp.QuoteMeta(phrase)
+		if len(phrase) >= 2 && strings.HasPrefix(phrase, "/") && strings.HasSuffix(phrase, "/") {
+			expression = phrase[1 : len(phrase)-1]
+		}
+		pattern, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid `%s` value: %w", key, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// Session history export formats
+const (
+	ExportSRT  = "srt"
+	ExportJSON = "json"
+	ExportText = "text"
+)
+
+// GetExportOnExit returns the format the session history is exported to on exit, empty meaning no export.
+func (c *Configuration) GetExportOnExit() (string, error) {
+	switch format := strings.ToLower(c.ExportOnExit); format {
+	case "", ExportSRT, ExportJSON, ExportText:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid `export-on-exit` value: %s", c.ExportOnExit)
+	}
+}
+
+// GetNormalize returns the Unicode normalization form applied to the extracted text and its translation.
+func (c *Configuration) GetNormalize() (string, error) {
+	switch strings.ToUpper(c.Normalize) {
+	case strings.ToUpper(cleanup.NormalizeNone):
+		return cleanup.NormalizeNone, nil
+	case cleanup.NormalizeNFC:
+		return cleanup.NormalizeNFC, nil
+	case cleanup.NormalizeNFKC:
+		return cleanup.NormalizeNFKC, nil
+	default:
+		return "", fmt.Errorf("invalid `normalize` value: %s", c.Normalize)
+	}
+}
@@ -0,0 +1,207 @@
+package configuration
+
+import (
+	"image/color"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/spf13/viper"
+)
+
+func TestParseColorString(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		want  color.RGBA
+		error string
+	}{
+		{"white", "#FFFFFF", color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF}, ""},
+		{"uppercase", "#1A2B3C", color.RGBA{R: 0x1A, G: 0x2B, B: 0x3C}, ""},
+		{"lowercase", "#1a2b3c", color.RGBA{R: 0x1A, G: 0x2B, B: 0x3C}, ""},
+		{"black", "#000000", color.RGBA{}, ""},
+		{"empty", "", color.RGBA{}, "color string length must be 7 but is 0"},
+		{"short", "#FFF", color.RGBA{}, "color string length must be 7 but is 4"},
+		{"with alpha", "#FFFFFF80", color.RGBA{}, "color string length must be 7 but is 9"},
+		{"missing hash", "0FFFFFF", color.RGBA{}, "unable to parse color string 0FFFFFF"},
+		{"non-hex", "#GGGGGG", color.RGBA{}, "unable to parse color string #GGGGGG"},
+		{"named", "magenta", color.RGBA{}, "unable to parse color string magenta"},
+		{"rgb", "rgb(0)", color.RGBA{}, "color string length must be 7 but is 6"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := parseColorString(test.s)
+			if test.error != "" {
+				if err == nil || err.Error() != test.error {
+					t.Fatalf("parseColorString(%q) error = %v, want %q", test.s, err, test.error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseColorString(%q) error = %v", test.s, err)
+			}
+			if c != test.want {
+				t.Errorf("parseColorString(%q) = %v, want %v", test.s, c, test.want)
+			}
+		})
+	}
+}
+
+func TestFontGetColor(t *testing.T) {
+	f := Font{Color: "#102030"}
+	c, err := f.GetColor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xFF}); c != want {
+		t.Errorf("GetColor() = %v, want %v, the font being opaque", c, want)
+	}
+
+	f.Color = "red"
+	if _, err := f.GetColor(); err == nil || err.Error() != "invalid `subs.font.color` value: color string length must be 7 but is 3" {
+		t.Errorf("GetColor() error = %v", err)
+	}
+}
+
+func TestBackgroundGetColor(t *testing.T) {
+	for _, opacity := range []int{0, 0x80, 0xFF} {
+		b := Background{Color: "#102030", Opacity: opacity}
+		c, err := b.GetColor()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: uint8(opacity)}); c != want {
+			t.Errorf("GetColor() with opacity %d = %v, want %v", opacity, c, want)
+		}
+	}
+
+	b := Background{Color: "#12345", Opacity: 0xFF}
+	if _, err := b.GetColor(); err == nil || err.Error() != "invalid `subs.background.color` value: color string length must be 7 but is 6" {
+		t.Errorf("GetColor() error = %v", err)
+	}
+}
+
+func TestClampFontSize(t *testing.T) {
+	tests := []struct {
+		size, want int
+	}{
+		{-5, DefaultFontSize},
+		{0, DefaultFontSize},
+		{1, MinFontSize},
+		{MinFontSize, MinFontSize},
+		{32, 32},
+		{MaxFontSize, MaxFontSize},
+		{10000, MaxFontSize},
+	}
+	for _, test := range tests {
+		if size := clampFontSize("subs.font.size", test.size); size != test.want {
+			t.Errorf("clampFontSize(%d) = %d, want %d", test.size, size, test.want)
+		}
+	}
+}
+
+func TestLanguageFontGetSize(t *testing.T) {
+	tests := []struct {
+		size, want int
+	}{
+		{0, 30}, // Not set: the default size
+		{20, 20},
+		{2, MinFontSize},
+		{500, MaxFontSize},
+	}
+	for _, test := range tests {
+		l := LanguageFont{Size: test.size}
+		if size := l.GetSize("ja", 30); size != test.want {
+			t.Errorf("GetSize() with size %d = %d, want %d", test.size, size, test.want)
+		}
+	}
+}
+
+func TestDebug(t *testing.T) {
+	tests := []struct {
+		name string
+		yml  string
+		want Debug
+	}{
+		{"section", "debug:\n  enabled: true\n  annotate-screenshots: true\n", Debug{Enabled: true, AnnotateScreenshots: true}},
+		{"boolean", "debug: true\n", Debug{Enabled: true}},
+		{"unset", "refresh-rate: 5s\n", Debug{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yml")
+			if err := v.ReadConfig(strings.NewReader(test.yml)); err != nil {
+				t.Fatal(err)
+			}
+			var config Configuration
+			if err := v.Unmarshal(&config, viper.DecodeHook(debugHook)); err != nil {
+				t.Fatal(err)
+			}
+			if config.Debug != test.want {
+				t.Errorf("debug = %+v, want %+v", config.Debug, test.want)
+			}
+		})
+	}
+}
+
+func TestGetBlocklist(t *testing.T) {
+	tests := []struct {
+		name    string
+		phrase  string
+		text    string
+		matches bool
+	}{
+		{"literal", "Continue?", "Continue?", true},
+		{"literal question mark", "Continue?", "Continu", false},
+		{"literal parentheses", "(Demo)", "Title (Demo)", true},
+		{"literal without parentheses", "(Demo)", "Demo", false},
+		{"regular expression", "/(?i)demo version/", "DEMO VERSION", true},
+		{"slash only", "/", "a/b", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &Configuration{Blocklist: []string{test.phrase}, Allowlist: []string{test.phrase}}
+			for _, get := range []func() ([]*regexp.Regexp, error){config.GetBlocklist, config.GetAllowlist} {
+				patterns, err := get()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if matches := patterns[0].MatchString(test.text); matches != test.matches {
+					t.Errorf("%q matches %q: %t, want %t", test.phrase, test.text, matches, test.matches)
+				}
+			}
+		})
+	}
+
+	config := &Configuration{Blocklist: []string{"/(/"}}
+	if _, err := config.GetBlocklist(); err == nil || !strings.HasPrefix(err.Error(), "invalid `blocklist` value") {
+		t.Errorf("GetBlocklist() error = %v, want an invalid `blocklist` value", err)
+	}
+}
+
+func TestReconnecting(t *testing.T) {
+	tests := []struct {
+		name           string
+		api            string
+		reconnectAfter int
+		reconnecting   bool
+	}{
+		{"google", "google", 5, true},
+		{"google without reconnection", "google", 0, false},
+		{"deepl", "deepl", 5, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Configuration{Translator: Translator{API: test.api}, OnError: OnError{ReconnectAfter: test.reconnectAfter}}
+			translator, err := c.reconnecting(func() (translate.Translator, error) { return translate.NewNone(), nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := translator.(*translate.Reconnecting); ok != test.reconnecting {
+				t.Errorf("reconnecting() = %T, want reconnecting %t", translator, test.reconnecting)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package configuration
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/spf13/viper"
+)
+
+// readFromEnvironment reads the configuration with the given environment variables only, without any configuration
+// file to be found.
+func readFromEnvironment(t *testing.T, env map[string]string) (*Configuration, error) {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		viper.Reset()
+	})
+	t.Setenv("HOME", dir)
+	for _, key := range []string{translatorAPIEnv, authenticationKeyEnv, endpointEnv, windowTitleEnv} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	viper.Reset()
+	return Read()
+}
+
+func TestReadFromEnvironment(t *testing.T) {
+	config, err := readFromEnvironment(t, map[string]string{
+		translatorAPIEnv:     "deepl",
+		authenticationKeyEnv: "key:fx",
+		windowTitleEnv:       "Tales",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Translator.API != "deepl" || config.Translator.AuthenticationKey != "key:fx" {
+		t.Errorf("translator = %s with key %q, want deepl with the key from the environment", config.Translator.API, config.Translator.AuthenticationKey)
+	}
+	title, err := config.GetWindowTitle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Tales" {
+		t.Errorf("GetWindowTitle() = %q, want %q", title, "Tales")
+	}
+	if config.GetRefreshRate() <= 0 {
+		t.Error("refresh rate not read from the default configuration")
+	}
+}
+
+func TestReadFromEnvironmentWithoutWindowTitle(t *testing.T) {
+	config, err := readFromEnvironment(t, map[string]string{translatorAPIEnv: "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.GetWindowTitle(); err == nil {
+		t.Errorf("GetWindowTitle() = %q, want an error for the placeholder of the default configuration", config.WindowTitle)
+	}
+}
+
+func TestReadFromIncompleteEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"nothing", nil},
+		{"deepl without key", map[string]string{translatorAPIEnv: "deepl"}},
+		{"libretranslate without endpoint", map[string]string{translatorAPIEnv: "libretranslate"}},
+		{"window title only", map[string]string{windowTitleEnv: "Tales"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readFromEnvironment(t, test.env)
+			var configNotFound viper.ConfigFileNotFoundError
+			if !errors.As(err, &configNotFound) {
+				t.Errorf("Read() error = %v, want the configuration file not to be found", err)
+			}
+		})
+	}
+}
+
+func TestTranslatorFromEnvironment(t *testing.T) {
+	config, err := readFromEnvironment(t, map[string]string{
+		translatorAPIEnv:                    "none",
+		"INTERPRETER_TRANSLATOR_TO":         "fr",
+		"INTERPRETER_TRANSLATOR_CACHE_SIZE": "10",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target := config.Translator.Target(); target != "fr" {
+		t.Errorf("Target() = %q, want %q", target, "fr")
+	}
+	if config.Translator.CacheSize != 10 {
+		t.Errorf("cache size = %d, want 10", config.Translator.CacheSize)
+	}
+
+	translator, err := config.GetTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer translator.Close()
+	if _, ok := translator.(*translate.Cached); !ok {
+		t.Errorf("GetTranslator() = %T, want a cached translator", translator)
+	}
+	translation, err := translator.Translate(context.Background(), "こんにちは")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation != "こんにちは" {
+		t.Errorf("Translate() = %q, want the text unchanged", translation)
+	}
+}
+
+func TestUnknownTranslatorFromEnvironment(t *testing.T) {
+	config, err := readFromEnvironment(t, map[string]string{translatorAPIEnv: "bogus"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translator, err := config.GetTranslator(); err == nil {
+		translator.Close()
+		t.Error("GetTranslator() succeeded with an unknown translator, want an error")
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+)
+
+// blockOutline is the color of the text blocks outlines, the words being outlined with their confidence color.
+var blockOutline = color.RGBA{R: 0x30, G: 0x90, B: 0xFF, A: 0xFF}
+
+// annotateScreenshot returns a copy of the screenshot with the blocks and words found by OCR outlined, each word being
+// labeled with its confidence and colored the same way as the confidence overlay.
+func annotateScreenshot(screenshot image.Image, annotation *visionpb.TextAnnotation, threshold configuration.ConfidenceThreshold) image.Image {
+	img := image.NewRGBA(screenshot.Bounds())
+	draw.Draw(img, img.Bounds(), screenshot, img.Bounds().Min, draw.Src)
+	if annotation == nil {
+		return img
+	}
+
+	drawer := font.Drawer{Dst: img, Face: basicfont.Face7x13}
+	for _, page := range annotation.Pages {
+		for _, b := range page.Blocks {
+			outline(img, boundingRectangle(b.BoundingBox), blockOutline)
+			for _, paragraph := range b.Paragraphs {
+				for _, word := range paragraph.Words {
+					language := detectedLanguage(word.Property, paragraph.Property, b.Property, page.Property)
+					c := confidenceColor(scoredWord{confidence: word.Confidence, threshold: threshold.For(language)})
+					bounds := boundingRectangle(word.BoundingBox)
+					outline(img, bounds, c)
+
+					// Label the word above its box, or below it at the top of the screenshot
+					y := bounds.Min.Y - basicfont.Face7x13.Descent
+					if y-basicfont.Face7x13.Ascent < img.Bounds().Min.Y {
+						y = bounds.Max.Y + basicfont.Face7x13.Ascent
+					}
+					drawer.Src = image.NewUniform(c)
+					drawer.Dot = fixed.P(bounds.Min.X, y)
+					drawer.DrawString(fmt.Sprintf("%.2f", word.Confidence))
+				}
+			}
+		}
+	}
+	return img
+}
+
+// outline draws the border of the rectangle, 1 pixel wide.
+func outline(img draw.Image, r image.Rectangle, c color.Color) {
+	src := image.NewUniform(c)
+	for _, side := range []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1),
+		image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y),
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y),
+		image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y),
+	} {
+		draw.Draw(img, side, src, image.Point{}, draw.Src)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bquenin/interpreter/internal/cleanup"
+)
+
+// segment is a part of the previous text along with its translation.
+type segment struct {
+	source      string
+	translation string
+}
+
+// translateDiff only translates the text changed since the previous extraction, reusing the translation of the
+// segments left unchanged at its beginning. As translation isn't compositional, the text is translated as a whole
+// when the unchanged part is shorter than diffMinPrefix runes.
+func (a *App) translateDiff(ctx context.Context, text string) (string, error) {
+	previous := strings.Builder{}
+	for _, s := range a.diffSegments {
+		previous.WriteString(s.source)
+	}
+	prefix := len(cleanup.CommonPrefix(previous.String(), text))
+
+	// Reuse the whole segments within the common prefix
+	var kept []segment
+	reused := 0
+	for _, s := range a.diffSegments {
+		if reused+len(s.source) > prefix {
+			break
+		}
+		kept = append(kept, s)
+		reused += len(s.source)
+	}
+	if utf8.RuneCountInString(text[:reused]) < a.diffMinPrefix {
+		kept, reused = nil, 0
+	}
+
+	changed := text[reused:]
+	if strings.TrimSpace(changed) != "" {
+		translation, err := a.translator.Translate(ctx, strings.TrimSpace(changed))
+		if err != nil {
+			return "", err
+		}
+		kept = append(kept, segment{changed, translation})
+	}
+	a.diffSegments = kept
+
+	translations := make([]string, 0, len(kept))
+	for _, s := range kept {
+		translations = append(translations, s.translation)
+	}
+	return strings.Join(translations, " "), nil
+}
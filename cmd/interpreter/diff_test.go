@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+func TestTranslateDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		diffMinPrefix int
+		texts         []string
+		want          string
+		calls         []string
+	}{
+		{"identical", 0, []string{"Hello. ", "Hello. "}, "en:Hello.", []string{"Hello."}},
+		{"appended", 0, []string{"Hello. ", "Hello. How are you?"}, "en:Hello. en:How are you?", []string{"Hello.", "How are you?"}},
+		{"edited", 0, []string{"Hello. How are you?", "Hello. Who are you?"}, "en:Hello. Who are you?", []string{"Hello. How are you?", "Hello. Who are you?"}},
+		{"edited after a segment", 0, []string{"Hello. ", "Hello. How are you?", "Hello. Who are you?"}, "en:Hello. en:Who are you?", []string{"Hello.", "How are you?", "Who are you?"}},
+		{"short prefix", 10, []string{"Hello. ", "Hello. How are you?"}, "en:Hello. How are you?", []string{"Hello.", "Hello. How are you?"}},
+		{"emptied", 0, []string{"Hello. ", ""}, "", []string{"Hello."}},
+		{"empty", 0, []string{"", "  "}, "", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &translatetest.Fake{Prefix: "en:"}
+			a := &App{translator: fake, diffMinPrefix: test.diffMinPrefix}
+			var translation string
+			for _, text := range test.texts {
+				var err error
+				if translation, err = a.translateDiff(context.Background(), text); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if translation != test.want {
+				t.Errorf("translateDiff() = %q, want %q", translation, test.want)
+			}
+			if calls := fake.Calls(); !reflect.DeepEqual(calls, test.calls) {
+				t.Errorf("translator called with %q, want %q", calls, test.calls)
+			}
+		})
+	}
+}
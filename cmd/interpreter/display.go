@@ -0,0 +1,100 @@
+package main
+
+import (
+	"image"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	windowTitle          = "Interpreter"
+	monitorCheckInterval = time.Second
+	topmostInterval      = time.Second
+	moveSettleDelay      = 500 * time.Millisecond
+)
+
+// keepOnMonitor moves the window back to the configured monitor and within its bounds.
+// Monitors are listed again at every check so that plugged or unplugged monitors and resolution changes are handled.
+func (a *App) keepOnMonitor() {
+	if a.monitor < 0 || time.Since(a.lastMonitorCheck) < monitorCheckInterval {
+		return
+	}
+	a.lastMonitorCheck = time.Now()
+
+	monitors := ebiten.AppendMonitors(nil)
+	if a.monitor >= len(monitors) {
+		if !a.monitorMissing {
+			log.Warn().Msgf("monitor %d not found, %d monitor(s) available", a.monitor, len(monitors))
+			a.monitorMissing = true
+		}
+		return
+	}
+	a.monitorMissing = false
+
+	if monitor := monitors[a.monitor]; ebiten.Monitor() != monitor {
+		ebiten.SetMonitor(monitor)
+	}
+
+	// Clamp the window position to the monitor
+	screenWidth, screenHeight := ebiten.ScreenSizeInFullscreen()
+	width, height := ebiten.WindowSize()
+	x, y := ebiten.WindowPosition()
+	clampedX, clampedY := clamp(x, 0, screenWidth-width), clamp(y, 0, screenHeight-height)
+	if clampedX != x || clampedY != y {
+		ebiten.SetWindowPosition(clampedX, clampedY)
+	}
+}
+
+// keepOnTop periodically reasserts the overlay as topmost window, as fullscreen games tend to steal the z-order.
+func (a *App) keepOnTop() {
+	if !a.alwaysOnTop || time.Since(a.lastTopmost) < topmostInterval {
+		return
+	}
+	a.lastTopmost = time.Now()
+	if !bringToTop(windowTitle) {
+		log.Warn().Msg("unable to keep the window on top, disabling always-on-top")
+		a.alwaysOnTop = false
+	}
+}
+
+// isMoving reports whether the window is being moved or resized, or was less than the settle delay ago.
+// Capture pauses meanwhile, not to capture the window itself over the game nor waste calls on the frames of the drag.
+func (a *App) isMoving() bool {
+	if !a.pauseWhileMoving {
+		return false
+	}
+	x, y := ebiten.WindowPosition()
+	width, height := ebiten.WindowSize()
+	bounds := image.Rect(x, y, x+width, y+height)
+	if bounds != a.windowBounds {
+		if !a.windowBounds.Empty() {
+			a.movedAt = time.Now()
+		}
+		a.windowBounds = bounds
+	}
+	return time.Since(a.movedAt) < moveSettleDelay
+}
+
+// updateClickThrough lets clicks go through the window unless the click-through modifier is held.
+func (a *App) updateClickThrough() {
+	if a.clickThrough == "" {
+		return
+	}
+	passthrough := !isModifierPressed(a.clickThrough)
+	if passthrough != a.mousePassthrough {
+		ebiten.SetWindowMousePassthrough(passthrough)
+		a.mousePassthrough = passthrough
+	}
+}
+
+func clamp(v, min, max int) int {
+	if v > max {
+		v = max
+	}
+	if v < min {
+		v = min
+	}
+	return v
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font/basicfont"
+)
+
+// failureY is the baseline of the error, below the lines of the decorated window message.
+const failureY = 60
+
+var failureColor = color.RGBA{R: 0xFF, G: 0x30, B: 0x30, A: 0xFF}
+
+// failure holds the error of the last refresh, if it failed, for Draw to surface it.
+type failure struct {
+	mu      sync.Mutex
+	message string
+}
+
+func (f *failure) set(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.message = ""
+	if err != nil {
+		f.message = err.Error()
+	}
+}
+
+func (f *failure) get() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.message
+}
+
+// draw renders the error of the last refresh in red, below the decorated window message.
+func (f *failure) draw(screen *ebiten.Image) {
+	if message := f.get(); message != "" {
+		text.Draw(screen, "[refresh failed] "+message, basicfont.Face7x13, 0, failureY, failureColor)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+
+	"github.com/rs/zerolog/log"
+)
+
+// isTargetFocused reports whether capture should go on according to the focus of the captured window.
+// When the captured window loses the focus, capture is paused and the subtitles are kept for the grace period.
+// Focusing the overlay itself doesn't count as a focus loss, and the foreground window is always focused.
+func (a *App) isTargetFocused() bool {
+	if a.focusGracePeriod <= 0 || a.windowTitle == configuration.ForegroundWindow {
+		return true
+	}
+
+	title, own, ok := foregroundWindow()
+	if !ok || own || strings.Contains(strings.ToLower(title), strings.ToLower(a.windowTitle)) {
+		if !a.focusLostAt.IsZero() {
+			log.Info().Msg("captured window focused again, resuming")
+			a.focusLostAt = time.Time{}
+			a.lastUpdate = time.Time{} // Refresh right away
+		}
+		return true
+	}
+
+	if a.focusLostAt.IsZero() {
+		log.Info().Msg("captured window lost focus, pausing")
+		a.focusLostAt = time.Now()
+	}
+	if subs, _ := a.getSubs(); subs != "" && time.Since(a.focusLostAt) >= a.focusGracePeriod {
+		a.clearSubs()
+		a.history.add("", "", time.Now())
+		a.updateLiveFile()
+	}
+	return false
+}
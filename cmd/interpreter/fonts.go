@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// languageFace returns the subtitles face of the target language, from subs.font.languages if it's overridden there,
+// from the default font otherwise. All the overrides are loaded, so that a broken one is reported whatever the target.
+func languageFace(ttf *opentype.Font, config *configuration.Font, target string) (font.Face, error) {
+	faces := make(map[string]font.Face, len(config.Languages))
+	for language, override := range config.Languages {
+		overrideTTF := ttf
+		if override.File != "" {
+			data, err := os.ReadFile(override.File)
+			if err != nil {
+				return nil, fmt.Errorf("invalid `subs.font.languages.%s.file` value: %w", language, err)
+			}
+			if overrideTTF, err = opentype.Parse(data); err != nil {
+				return nil, fmt.Errorf("invalid `subs.font.languages.%s.file` value: %w", language, err)
+			}
+		}
+		face, err := newFace(overrideTTF, override.GetSize(language, config.GetSize()))
+		if err != nil {
+			return nil, err
+		}
+		faces[language] = face
+	}
+
+	if language, ok := config.Language(target); ok {
+		log.Info().Msgf("using the %s font override", language)
+		return faces[language], nil
+	}
+	return newFace(ttf, config.GetSize())
+}
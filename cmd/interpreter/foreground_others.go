@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+// foregroundWindow returns the title of the focused window and whether it belongs to this process.
+// It's only supported on Windows.
+func foregroundWindow() (title string, own bool, ok bool) {
+	return "", false, false
+}
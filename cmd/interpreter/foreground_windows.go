@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	getWindowTextLength = windows.NewLazySystemDLL("user32.dll").NewProc("GetWindowTextLengthW")
+	getWindowText       = windows.NewLazySystemDLL("user32.dll").NewProc("GetWindowTextW")
+)
+
+// foregroundWindow returns the title of the focused window and whether it belongs to this process.
+func foregroundWindow() (title string, own bool, ok bool) {
+	hwnd := windows.GetForegroundWindow()
+	if hwnd == 0 {
+		return "", false, false
+	}
+
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(hwnd, &pid); err == nil && int(pid) == os.Getpid() {
+		own = true
+	}
+
+	length, _, _ := getWindowTextLength.Call(uintptr(hwnd))
+	buffer := make([]uint16, length+1)
+	getWindowText.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&buffer[0])), length+1)
+	return windows.UTF16ToString(buffer), own, true
+}
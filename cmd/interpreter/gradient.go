@@ -0,0 +1,54 @@
+package main
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// drawGradientText draws the subtitles box text with a color going from the first gradient color to the second one.
+// The text is drawn in white offscreen, then copied one pixel row or column at a time with the interpolated color.
+func (a *App) drawGradientText(screen *ebiten.Image, subtitles string, x int, box image.Point, alpha float32) {
+	if box.X <= 0 || box.Y <= 0 {
+		return
+	}
+	if a.gradientImage == nil || a.gradientImage.Bounds().Size() != box {
+		if a.gradientImage != nil {
+			a.gradientImage.Dispose()
+		}
+		a.gradientImage = ebiten.NewImage(box.X, box.Y)
+	}
+	a.gradientImage.Clear()
+	text.Draw(a.gradientImage, subtitles, a.subsFont, 0, a.subsFont.Metrics().Height.Round(), color.White)
+
+	steps := box.Y
+	if a.gradientDirection == configuration.GradientHorizontal {
+		steps = box.X
+	}
+	last := float64(steps - 1)
+	if last == 0 {
+		last = 1
+	}
+	for i := 0; i < steps; i++ {
+		strip := image.Rect(0, i, box.X, i+1)
+		if a.gradientDirection == configuration.GradientHorizontal {
+			strip = image.Rect(i, 0, i+1, box.Y)
+		}
+		options := &ebiten.DrawImageOptions{}
+		options.GeoM.Translate(float64(x+strip.Min.X), float64(strip.Min.Y))
+		options.ColorScale.ScaleWithColor(interpolate(a.gradient[0], a.gradient[1], float64(i)/last))
+		options.ColorScale.ScaleAlpha(alpha)
+		screen.DrawImage(a.gradientImage.SubImage(strip).(*ebiten.Image), options)
+	}
+}
+
+// interpolate returns the color at ratio between from (0) and to (1).
+func interpolate(from, to color.RGBA, ratio float64) color.RGBA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*ratio)
+	}
+	return color.RGBA{R: mix(from.R, to.R), G: mix(from.G, to.G), B: mix(from.B, to.B), A: mix(from.A, to.A)}
+}
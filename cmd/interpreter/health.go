@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/bquenin/interpreter/internal/translate"
+)
+
+const healthCheckTimeout = 10 * time.Second
+
+// checkTranslator makes sure the translator is usable, so that bad credentials fail upfront rather than on the first translation.
+// The translators without health check are tried with a trivial translation.
+func checkTranslator(translator translate.Translator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	if checker, ok := translate.AsHealthChecker(translator); ok {
+		return checker.HealthCheck(ctx)
+	}
+	_, err := translator.Translate(ctx, "ok")
+	return err
+}
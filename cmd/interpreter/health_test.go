@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+// failingChecker is a translator whose health check fails, as with bad credentials.
+type failingChecker struct {
+	translatetest.Fake
+}
+
+func (f *failingChecker) HealthCheck(ctx context.Context) error {
+	return translate.ErrAuth
+}
+
+func TestCheckTranslatorWrapped(t *testing.T) {
+	checker := &failingChecker{}
+	cached, err := translate.NewCached(checker, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconnecting, err := translate.NewReconnecting(func() (translate.Translator, error) { return cached, nil }, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The health check is reached through the wrappers, rather than trying a translation
+	if err := checkTranslator(reconnecting); !errors.Is(err, translate.ErrAuth) {
+		t.Errorf("checkTranslator() = %v, want %v", err, translate.ErrAuth)
+	}
+	if calls := checker.Calls(); len(calls) != 0 {
+		t.Errorf("translator called with %q, want the health check only", calls)
+	}
+}
+
+func TestCheckTranslatorWithoutHealthCheck(t *testing.T) {
+	fake := &translatetest.Fake{Prefix: "en:"}
+	reconnecting, err := translate.NewReconnecting(func() (translate.Translator, error) { return fake, nil }, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkTranslator(reconnecting); err != nil {
+		t.Fatal(err)
+	}
+	if calls, want := fake.Calls(), []string{"ok"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("translator called with %q, want %q", calls, want)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+)
+
+// historySize is the number of subtitles kept in the history, the oldest ones being dropped first.
+const historySize = 10_000
+
+type historyEntry struct {
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Source      string    `json:"source"`
+	Translation string    `json:"translation"`
+}
+
+// history keeps the subtitles displayed during the session.
+type history struct {
+	mutex   sync.Mutex
+	start   time.Time
+	entries []historyEntry
+}
+
+func newHistory() *history {
+	return &history{start: time.Now()}
+}
+
+// add ends the subtitles currently displayed at the given time and starts the new ones, unless they are empty.
+func (h *history) add(source, translation string, at time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if n := len(h.entries); n > 0 && h.entries[n-1].End.IsZero() {
+		h.entries[n-1].End = at
+	}
+	if translation == "" {
+		return
+	}
+	if len(h.entries) == historySize {
+		h.entries = h.entries[1:]
+	}
+	h.entries = append(h.entries, historyEntry{Start: at, Source: source, Translation: translation})
+}
+
+// lookup returns the last translation displayed for the given source text.
+func (h *history) lookup(source string) (string, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if h.entries[i].Source == source {
+			return h.entries[i].Translation, true
+		}
+	}
+	return "", false
+}
+
+// export writes the history in the given format, the subtitles still displayed ending at the given time.
+func (h *history) export(w io.Writer, format string, at time.Time) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	entries := make([]historyEntry, len(h.entries))
+	copy(entries, h.entries)
+	if n := len(entries); n > 0 && entries[n-1].End.IsZero() {
+		entries[n-1].End = at
+	}
+
+	switch format {
+	case configuration.ExportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case configuration.ExportSRT:
+		for i, entry := range entries {
+			if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", i+1, srtTimestamp(entry.Start.Sub(h.start)), srtTimestamp(entry.End.Sub(h.start)), entry.Translation); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, entry := range entries {
+			if _, err := fmt.Fprintf(w, "[%s] %s\n%s\n\n", entry.Start.Format("15:04:05"), entry.Source, entry.Translation); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exportFile writes the history to a file of the current folder named after the session start.
+func (h *history) exportFile(format string) (string, error) {
+	extension := format
+	if format == configuration.ExportText {
+		extension = "txt"
+	}
+	name := fmt.Sprintf("interpreter-%s.%s", h.start.Format("20060102-150405"), extension)
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if err := h.export(f, format, time.Now()); err != nil {
+		f.Close()
+		return "", err
+	}
+	return name, f.Close()
+}
+
+// srtTimestamp formats a duration as hours:minutes:seconds,milliseconds.
+func srtTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3_600_000, ms/60_000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/rs/zerolog/log"
+)
+
+// parseKey returns the ebiten key matching a configured key name, such as "C" or "F5".
+func parseKey(setting, name string) (ebiten.Key, error) {
+	var key ebiten.Key
+	if err := key.UnmarshalText([]byte(name)); err != nil {
+		return key, fmt.Errorf("invalid `%s` value: %w", setting, err)
+	}
+	return key, nil
+}
+
+// handleCopyKeys copies the displayed translation or its source text to the clipboard.
+func (a *App) handleCopyKeys() {
+	translation, source := a.getSubs()
+	if inpututil.IsKeyJustPressed(a.copyTranslationKey) {
+		copyToClipboard("translation", translation)
+	}
+	if inpututil.IsKeyJustPressed(a.copySourceKey) {
+		copyToClipboard("source text", source)
+	}
+}
+
+func copyToClipboard(what, s string) {
+	if s == "" {
+		log.Info().Msgf("no %s to copy", what)
+		return
+	}
+	if err := clipboard.WriteAll(s); err != nil {
+		log.Warn().Err(err).Msgf("unable to copy %s to clipboard", what)
+		return
+	}
+	log.Info().Msgf("%s copied to clipboard", what)
+}
+
+// handlePauseKey pauses or resumes the capture and translation, the subtitles displayed being kept meanwhile.
+func (a *App) handlePauseKey() {
+	if !inpututil.IsKeyJustPressed(a.pauseKey) {
+		return
+	}
+	a.paused = !a.paused
+	if a.paused {
+		log.Info().Msg("paused")
+		return
+	}
+	log.Info().Msg("resumed")
+	a.lastUpdate = time.Time{} // Refresh right away
+}
+
+// handleCycleLanguageKey switches to the next target language, the text displayed being translated again.
+func (a *App) handleCycleLanguageKey() {
+	if a.targetSwitch == nil || !inpututil.IsKeyJustPressed(a.cycleLanguageKey) {
+		return
+	}
+	a.target = (a.target + 1) % len(a.targets)
+	a.targetSwitch.Select(a.target)
+	if cachedSwitch, ok := a.cachedTranslator.(*translate.Switch); ok {
+		cachedSwitch.Select(a.target)
+	}
+	a.subsFont = a.targetFaces[a.target]
+	if a.ticker != nil {
+		a.ticker.face = a.subsFont
+	}
+	a.setLastText("")
+	a.lastUpdate = time.Time{}
+	log.Info().Msgf("translating to %s", a.targets[a.target])
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/rs/zerolog/log"
+)
+
+// lockLanguage counts the consecutive detections of the same language and uses it as the OCR hint for the rest of the
+// session once it's been detected enough times in a row.
+func (a *App) lockLanguage(language string) {
+	if language == "" {
+		return
+	}
+	if language != a.languageCandidate {
+		a.languageCandidate, a.languageDetections = language, 0
+	}
+	if a.languageDetections++; a.languageDetections < a.languageLockAfter {
+		return
+	}
+	a.detectedLanguage = language
+	log.Info().Msgf("detected language: %s", language)
+}
+
+// handleResetLanguageKey forgets the detected language, for it to be detected again.
+func (a *App) handleResetLanguageKey() {
+	if a.languageLockAfter <= 0 || !inpututil.IsKeyJustPressed(a.resetLanguageKey) {
+		return
+	}
+	a.detectedLanguage, a.languageCandidate, a.languageDetections = "", "", 0
+	log.Info().Msg("detected language reset")
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/ocr"
+	"github.com/bquenin/interpreter/internal/ocr/ocrtest"
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+func TestAnnotateLanguageHints(t *testing.T) {
+	tests := []struct {
+		name             string
+		languageHints    []string
+		detectedLanguage string
+		lastText         string
+		want             ocr.Options
+	}{
+		{"none", nil, "", "", ocr.Options{}},
+		{"detected", nil, "ja", "", ocr.Options{LanguageHints: []string{"ja"}}},
+		{"configured over detected", []string{"ko"}, "ja", "", ocr.Options{LanguageHints: []string{"ko"}}},
+		{"script", nil, "", "こんにちは", ocr.Options{LanguageHints: []string{"ja"}}},
+		{"configured over script", []string{"ko"}, "", "こんにちは", ocr.Options{LanguageHints: []string{"ko"}}},
+		{"latin script", []string{"ko"}, "", "Loading", ocr.Options{LanguageHints: []string{"ko"}, Sparse: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			detector := &ocrtest.Fake{}
+			a := newTestApp(t, detector, &translatetest.Fake{})
+			a.languageHints, a.detectedLanguage, a.scriptSwitching = test.languageHints, test.detectedLanguage, true
+			a.setLastText(test.lastText)
+
+			if _, err := a.annotate(image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+				t.Fatal(err)
+			}
+			if options := detector.Options(); len(options) != 1 || !reflect.DeepEqual(options[0], test.want) {
+				t.Errorf("OCR options = %+v, want %+v", options, test.want)
+			}
+		})
+	}
+}
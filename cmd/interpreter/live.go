@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+// updateLiveFile replaces the content of the live file with the current subtitles, for streaming tools such as OBS.
+func (a *App) updateLiveFile() {
+	if a.liveFile == "" {
+		return
+	}
+	subs, _ := a.getSubs()
+	if err := writeAtomically(a.liveFile, subs); err != nil {
+		log.Warn().Err(err).Msg("unable to update the live file")
+	}
+}
+
+// writeAtomically writes to a temporary file renamed afterwards, so that readers never see the file half-written.
+func writeAtomically(name, content string) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // No-op once renamed
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
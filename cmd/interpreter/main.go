@@ -9,13 +9,20 @@ import (
 	"image"
 	"image/color"
 	"image/jpeg"
+	_ "image/png"
 	"os"
+	"os/signal"
+	"regexp"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
-	"cloud.google.com/go/vision/apiv1"
-	"github.com/bquenin/captured"
 	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/bquenin/interpreter/internal/capture"
+	"github.com/bquenin/interpreter/internal/cleanup"
+	"github.com/bquenin/interpreter/internal/frame"
+	"github.com/bquenin/interpreter/internal/ocr"
 	"github.com/bquenin/interpreter/internal/translate"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -37,31 +44,141 @@ func init() {
 }
 
 type App struct {
-	visionClient        *vision.ImageAnnotatorClient
-	windowTitle         string
-	refreshRate         time.Duration
-	lastUpdate          time.Time
-	subsFont            font.Face
-	lastText            string
-	subs                string
-	confidenceThreshold float32
-	translator          translate.Translator
-	debug               bool
-	subsFontColor       color.RGBA
-	subsBackgroundColor color.RGBA
-}
-
-func filterTextByConfidence(annotation *visionpb.TextAnnotation, threshold float32) string {
+	ocr                    ocr.OCR
+	capturer               capture.Capturer
+	windowTitle            string
+	refreshRate            time.Duration
+	lastUpdate             time.Time
+	subsFont               font.Face
+	lastText               string
+	subs                   string
+	confidenceThreshold    configuration.ConfidenceThreshold
+	translator             translate.Translator
+	debug                  bool
+	subsFontColor          color.RGBA
+	subsBackgroundColor    color.RGBA
+	persist                bool
+	sceneCutThreshold      float64
+	lastScreenshot         image.Image
+	mode                   string
+	stripCJKSpaces         bool
+	monitor                int
+	lastMonitorCheck       time.Time
+	monitorMissing         bool
+	maxWidth               float64
+	clickThrough           string
+	mousePassthrough       bool
+	onErrorPolicy          string
+	onErrorRetries         int
+	frameCache             *frame.Cache
+	dedupThreshold         float64
+	stats                  stats
+	translatorName         string
+	statusLine             bool
+	statusCorner           string
+	minRegion              float64
+	scrollStitch           bool
+	scrollMaxLength        int
+	scrollText             string
+	incremental            bool
+	incrementalSource      string
+	incrementalTranslation string
+	subsSource             string
+	copyTranslationKey     ebiten.Key
+	copySourceKey          ebiten.Key
+	recordDir              string
+	transcript             *transcript
+	lastRecord             time.Time
+	focusGracePeriod       time.Duration
+	focusLostAt            time.Time
+	compareTranslator      translate.Translator
+	compareName            string
+	languageLockAfter      int
+	languageCandidate      string
+	languageDetections     int
+	resetLanguageKey       ebiten.Key
+	pauseKey               ebiten.Key
+	paused                 bool
+	cycleLanguageKey       ebiten.Key
+	targets                []string
+	targetFaces            []font.Face
+	target                 int
+	targetSwitch           *translate.Switch
+	detectedLanguage       string
+	languageHints          []string
+	excludedZones          []image.Rectangle
+	autoRefreshRate        *adaptiveRefreshRate
+	translationTimeout     time.Duration
+	autoTimeout            bool
+	ctx                    context.Context // Cancelled on shutdown
+	cancel                 context.CancelFunc
+	scriptSwitching        bool
+	normalize              string
+	alwaysOnTop            bool
+	lastTopmost            time.Time
+	history                *history
+	confidence             *confidenceOverlay
+	diffTranslation        bool
+	diffMinPrefix          int
+	diffSegments           []segment
+	gradient               []color.RGBA
+	gradientDirection      string
+	gradientImage          *ebiten.Image
+	staleThreshold         time.Duration
+	pendingSince           time.Time
+	displayedAt            time.Time
+	stdinLines             <-chan string
+	dehyphenate            bool
+	paragraphs             bool
+	cachedTranslator       translate.Translator // Caches the paragraphs, or the blocks in positional layout
+	blocklist              []*regexp.Regexp
+	allowlist              []*regexp.Regexp
+	liveFile               string
+	emptyTolerance         int
+	emptyStreak            int
+	groupByColor           bool
+	speakers               []color.RGBA
+	startAt                time.Time
+	selection              *blockSelection
+	positions              *positionedSubs
+	saveOnError            bool
+	annotateScreenshots    bool
+	useCache               bool
+	offline                bool
+	skipTrivial            float64
+	ticker                 *ticker
+	pageSeparator          string
+	splitPages             bool
+	pauseWhileMoving       bool
+	windowBounds           image.Rectangle
+	movedAt                time.Time
+	interrupted            chan os.Signal
+	refreshing             chan struct{} // Holds a value while a refresh is running
+	failure                failure
+	subsMu                 sync.Mutex // Guards subs, subsSource and lastText, written by the refreshes and read by Update and Draw
+}
+
+// filterTextByConfidence returns the text of the words above the confidence threshold, the paragraphs being joined with separator
+// and the pages with pageSeparator.
+func filterTextByConfidence(annotation *visionpb.TextAnnotation, threshold configuration.ConfidenceThreshold, lineHyphens bool, separator, pageSeparator string) string {
 	var buffer bytes.Buffer
 	for _, page := range annotation.Pages {
+		if buffer.Len() > 0 && pageSeparator != "" {
+			buffer.WriteString(pageSeparator)
+		}
+		pageStart := buffer.Len()
 		for _, block := range page.Blocks {
 			for _, paragraph := range block.Paragraphs {
+				if buffer.Len() > pageStart && !strings.HasSuffix(buffer.String(), separator) {
+					buffer.WriteString(separator)
+				}
 				for _, word := range paragraph.Words {
-					if word.Confidence < threshold {
+					language := detectedLanguage(word.Property, paragraph.Property, block.Property, page.Property)
+					if word.Confidence < threshold.For(language) {
 						continue
 					}
 					for _, s := range word.Symbols {
-						buffer.WriteString(s.Text)
+						buffer.WriteString(symbolText(s, lineHyphens))
 					}
 				}
 			}
@@ -70,126 +187,617 @@ func filterTextByConfidence(annotation *visionpb.TextAnnotation, threshold float
 	return buffer.String()
 }
 
-func (a *App) screenshot(windowTitle string) (image.Image, error) {
-	return captured.Captured.CaptureWindowByTitle(windowTitle, captured.CropTitle)
+// detectedLanguage returns the language detected by Vision for the first property having one, from the most to the least specific.
+func detectedLanguage(properties ...*visionpb.TextAnnotation_TextProperty) string {
+	for _, property := range properties {
+		if property != nil && len(property.DetectedLanguages) > 0 {
+			return property.DetectedLanguages[0].LanguageCode
+		}
+	}
+	return ""
 }
 
-func (a *App) annotate(image image.Image) (string, error) {
-	// Encode to JPEG
-	var buffer bytes.Buffer
-	if err := jpeg.Encode(&buffer, image, &jpeg.Options{Quality: 85}); err != nil {
-		return "", err
+// newCapturer returns the capturer of the configured capture backend.
+func newCapturer(config *configuration.Configuration) (capture.Capturer, error) {
+	backend, err := config.Capture.GetBackend()
+	if err != nil {
+		return nil, err
+	}
+	if backend == configuration.CaptureScreenRegion {
+		return capture.NewScreenRegion(config.Capture.GetRegion())
+	}
+	title, err := config.GetWindowTitle()
+	if err != nil {
+		return nil, err
 	}
+	switch {
+	case backend == configuration.CaptureChildWindow:
+		return capture.NewChildWindow(title, config.Capture.ChildClass)
+	case title == configuration.ForegroundWindow:
+		return capture.NewForeground()
+	default:
+		return capture.NewWindow(title, windowTitle), nil
+	}
+}
 
-	// Create image
-	img, err := vision.NewImageFromReader(&buffer)
+func (a *App) screenshot() (image.Image, error) {
+	start := time.Now()
+	screenshot, cached, err := a.frameCache.Get(a.capturer.Capture)
 	if err != nil {
-		return "", err
+		a.stats.recordError(stageCapture)
+		return nil, &stageError{stage: stageCapture, err: err}
 	}
+	a.stats.recordFrame(cached)
+	if cached {
+		log.Debug().Msgf("reused cached frame in %s", time.Since(start))
+	} else {
+		log.Debug().Msgf("captured frame in %s", time.Since(start))
+	}
+	return frame.RGBA(screenshot), nil
+}
 
+func (a *App) annotate(image image.Image) (*visionpb.TextAnnotation, error) {
 	// Extract text from image
-	annotation, err := a.visionClient.DetectDocumentText(context.Background(), img, nil)
+	options := ocr.Options{LanguageHints: a.languageHints}
+	if len(a.languageHints) == 0 && a.detectedLanguage != "" {
+		options.LanguageHints = []string{a.detectedLanguage}
+	}
+	if a.scriptSwitching {
+		a.switchScript(&options)
+	}
+	a.stats.recordOCR()
+	annotation, err := a.ocr.DetectText(a.ctx, image, options)
 	if err != nil {
-		return "", err
+		a.stats.recordError(stageOCR)
+		return nil, &stageError{stage: stageOCR, err: err}
 	}
 	if annotation == nil {
 		log.Warn().Msg("no text found")
+		return nil, nil
+	}
+
+	// Use the detected language as a hint for the next screenshots
+	if a.languageLockAfter > 0 && a.detectedLanguage == "" {
+		a.lockLanguage(ocr.DominantLanguage(annotation))
+	}
+	return annotation, nil
+}
+
+// switchScript tunes the OCR options to the script of the previous text: sparse detection and automatic language
+// for latin text such as loading screens, dense detection and a language hint for CJK text such as dialogues.
+// The configured language hints take precedence over the script.
+func (a *App) switchScript(options *ocr.Options) {
+	switch script := cleanup.DominantScript(a.getLastText()); script {
+	case "":
+	case cleanup.ScriptLatin:
+		options.Sparse = true
+	default:
+		if len(a.languageHints) == 0 {
+			options.LanguageHints = []string{script}
+		}
+	}
+}
+
+// extract filters out gibberish from the annotation and returns the text to translate.
+// In choices mode, the detected blocks are returned as well, in reading order.
+func (a *App) extract(annotation *visionpb.TextAnnotation) (string, []block) {
+	if annotation == nil {
 		return "", nil
 	}
+	if a.minRegion > 0 {
+		removeSmallBlocks(annotation, a.minRegion)
+	}
 
-	// Filter out gibberish
-	extractedText := filterTextByConfidence(annotation, a.confidenceThreshold)
+	var extractedText string
+	var blocks []block
+	switch a.mode {
+	case configuration.ModeChoices:
+		blocks = filterBlocksByConfidence(annotation, a.confidenceThreshold, a.dehyphenate)
+		sortByReadingOrder(blocks)
+		extractedText = joinBlocks(blocks)
+	default:
+		extractedText = filterTextByConfidence(annotation, a.confidenceThreshold, a.dehyphenate, a.paragraphSeparator(), a.pageSeparator)
+		if a.groupByColor || a.selection != nil || a.positions != nil { // The blocks are needed to tell the speakers apart, the one clicked, or where to draw them
+			blocks = filterBlocksByConfidence(annotation, a.confidenceThreshold, a.dehyphenate)
+			sortByReadingOrder(blocks)
+		}
+	}
+	if a.stripCJKSpaces {
+		extractedText = cleanup.StripCJKSpaces(extractedText)
+		for i := range blocks {
+			blocks[i].text = cleanup.StripCJKSpaces(blocks[i].text)
+		}
+	}
+	if a.dehyphenate {
+		extractedText = cleanup.Dehyphenate(extractedText)
+		for i := range blocks {
+			blocks[i].text = cleanup.Dehyphenate(blocks[i].text)
+		}
+	}
+	extractedText = cleanup.Normalize(extractedText, a.normalize)
+	for i := range blocks {
+		blocks[i].text = cleanup.Normalize(blocks[i].text, a.normalize)
+	}
 	if extractedText == "" {
-		log.Warn().Msgf("no text found with confidence threshold %f", a.confidenceThreshold)
+		log.Warn().Msgf("no text found with confidence threshold %v", a.confidenceThreshold)
 		return "", nil
 	}
 
 	log.Info().Msgf("extracted text: %s", extractedText)
-	return extractedText, nil
+	return extractedText, blocks
+}
+
+// process runs one pipeline iteration on the given screenshot and returns the subtitles to display.
+// The returned boolean is false when the subtitles should be left unchanged.
+func (a *App) process(screenshot image.Image) (string, bool, error) {
+	screenshot = frame.Mask(screenshot, a.excludedZones, frame.Neutral)
+	previous := a.lastScreenshot
+	sceneCut := a.isSceneCut(screenshot)
+	a.lastScreenshot = screenshot
+
+	annotation, err := a.annotate(screenshot)
+	if err != nil {
+		return "", false, err
+	}
+	if a.debug && a.annotateScreenshots {
+		annotated := annotateScreenshot(screenshot, annotation, a.confidenceThreshold)
+		if err := saveScreenshot(fmt.Sprintf("screenshot-%d.jpg", a.lastUpdate.UnixNano()), annotated); err != nil {
+			return "", false, err
+		}
+	}
+	if a.confidence != nil {
+		a.confidence.set(annotation, a.confidenceThreshold)
+	}
+	text, blocks := a.extract(annotation)
+	if a.selection != nil {
+		a.selection.setBlocks(blocks, screenshot.Bounds().Size())
+	}
+	if a.groupByColor {
+		a.assignSpeakers(screenshot, blocks)
+	}
+	if a.scrollStitch && a.mode == configuration.ModeSubtitles {
+		text = a.stitch(previous, screenshot, text)
+	}
+	return a.processText(text, blocks, sceneCut)
+}
+
+// processText translates the text extracted from a frame, or read from the standard input.
+// The returned boolean is false when the subtitles should be left unchanged.
+func (a *App) processText(text string, blocks []block, sceneCut bool) (string, bool, error) {
+	if len(a.blocklist) > 0 {
+		var blocked bool
+		if text, blocks, blocked = a.removeBlocked(text, blocks); blocked {
+			log.Debug().Msg("blocklisted text only, keeping the current subtitles")
+			return "", false, nil
+		}
+	}
+	if len(a.allowlist) > 0 && text != "" {
+		var allowed bool
+		if blocks, allowed = a.allowed(text, blocks); !allowed {
+			log.Debug().Msg("text not matching the allowlist, keeping the current subtitles")
+			return "", false, nil
+		}
+		if a.mode == configuration.ModeChoices {
+			text = joinBlocks(blocks)
+		}
+	}
+	if text != "" {
+		a.emptyStreak = 0
+	}
+	if text == a.getLastText() {
+		return "", false, nil
+	}
+	if text == "" {
+		if a.persist && !sceneCut {
+			return "", false, nil
+		}
+		// Brief OCR misses, during animations for instance, don't clear the subtitles
+		if a.emptyStreak++; a.emptyStreak < a.emptyTolerance && !sceneCut {
+			return "", false, nil
+		}
+		a.setLastText("")
+		return "", true, nil
+	}
+
+	if a.pendingSince.IsZero() {
+		a.pendingSince = time.Now()
+	}
+	translation, err := a.translate(text, blocks)
+	if err != nil {
+		a.stats.recordError(stageTranslate)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Warn().Msgf("translation timed out after %s, keeping the last translation", a.getTranslationTimeout())
+		return "", false, nil
+	}
+	if errors.Is(err, context.Canceled) { // Shutting down
+		return "", false, nil
+	}
+	if err != nil && a.useCache {
+		return a.fallBackToCache(text, err)
+	}
+	if err != nil {
+		return "", false, &stageError{stage: stageTranslate, text: text, err: err}
+	}
+	a.offline = false
+	translation = cleanup.Normalize(translation, a.normalize)
+	log.Info().Msgf("translated text: %s", translation)
+
+	a.setLastText(text)
+	a.pendingSince = time.Time{}
+	// Text already readable in the target language doesn't need subtitles
+	if a.skipTrivial > 0 && cleanup.Similarity(strings.ToLower(text), strings.ToLower(translation)) >= a.skipTrivial {
+		log.Debug().Msg("translation nearly identical to the text, hiding the subtitles")
+		return "", true, nil
+	}
+	if subs, _ := a.getSubs(); a.dedupThreshold > 0 && subs != "" && cleanup.Similarity(translation, subs) >= a.dedupThreshold {
+		log.Debug().Msg("translation similar to current subtitles, skipping")
+		return "", false, nil
+	}
+	if a.positions != nil {
+		a.acceptPositions()
+	}
+	return translation, true, nil
+}
+
+// getTranslationTimeout returns how long a translation may take, twice the refresh rate in auto mode, 0 meaning no limit.
+func (a *App) getTranslationTimeout() time.Duration {
+	if a.autoTimeout {
+		return 2 * a.getRefreshRate()
+	}
+	return a.translationTimeout
+}
+
+// translationContext returns a context cancelled on shutdown or once the translation timeout is exceeded.
+func (a *App) translationContext() (context.Context, context.CancelFunc) {
+	if timeout := a.getTranslationTimeout(); timeout > 0 {
+		return context.WithTimeout(a.ctx, timeout)
+	}
+	return context.WithCancel(a.ctx)
+}
+
+// translate translates the extracted text according to the display mode, within the translation timeout.
+func (a *App) translate(text string, blocks []block) (string, error) {
+	ctx, cancel := a.translationContext()
+	defer cancel()
+
+	var translation string
+	var err error
+	start := time.Now()
+	switch {
+	case a.mode == configuration.ModeChoices:
+		translation, err = a.translateChoices(ctx, blocks)
+	case a.positions != nil:
+		translation, err = a.translatePositions(ctx, blocks)
+	case a.groupByColor:
+		translation, err = a.translateBySpeaker(ctx, blocks)
+	case a.splitPages:
+		translation, err = a.translatePages(ctx, text)
+	case a.incremental:
+		translation, err = a.translateIncrementally(ctx, text)
+	case a.diffTranslation:
+		translation, err = a.translateDiff(ctx, text)
+	case a.paragraphs:
+		translation, err = a.translateParagraphs(ctx, text)
+	default:
+		translation, err = a.translator.Translate(ctx, text)
+	}
+	if err != nil {
+		return "", err
+	}
+	a.stats.recordTranslation(text, time.Since(start))
+
+	if a.compareTranslator != nil {
+		comparison, err := a.compareTranslator.Translate(ctx, text)
+		if err != nil {
+			return "", err
+		}
+		translation = fmt.Sprintf("[%s] %s\n[%s] %s", a.translatorName, translation, a.compareName, comparison)
+	}
+	return translation, nil
+}
+
+// stitch accumulates the text revealed by scrolling, so that text longer than the window is translated as a whole.
+func (a *App) stitch(previous, screenshot image.Image, text string) string {
+	if previous == nil || text == "" || frame.VerticalScroll(previous, screenshot) == 0 {
+		a.scrollText = text
+		return text
+	}
+	a.scrollText = cleanup.TrimStart(cleanup.MergeOverlap(a.scrollText, text), a.scrollMaxLength)
+	log.Debug().Msgf("scroll detected, stitched text: %s", a.scrollText)
+	return a.scrollText
+}
+
+// translateIncrementally only translates the sentences added since the previous extraction, such as typewriter dialogue.
+// Complete sentences are translated once, the sentence being written is translated again until it's complete.
+func (a *App) translateIncrementally(ctx context.Context, text string) (string, error) {
+	if !strings.HasPrefix(text, a.incrementalSource) {
+		a.incrementalSource, a.incrementalTranslation = "", ""
+	}
+
+	var tail string
+	for _, sentence := range cleanup.SplitSentences(text[len(a.incrementalSource):]) {
+		if strings.TrimSpace(sentence) == "" {
+			continue
+		}
+		translation, err := a.translator.Translate(ctx, strings.TrimSpace(sentence))
+		if err != nil {
+			return "", err
+		}
+		if !cleanup.IsCompleteSentence(sentence) {
+			tail = translation
+			continue
+		}
+		a.incrementalSource += sentence
+		a.incrementalTranslation = strings.TrimSpace(a.incrementalTranslation + " " + translation)
+	}
+	return strings.TrimSpace(a.incrementalTranslation + " " + tail), nil
+}
+
+// translateChoices translates each block separately and renders them as a numbered list.
+func (a *App) translateChoices(ctx context.Context, blocks []block) (string, error) {
+	choices := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		translation, err := a.translator.Translate(ctx, b.text)
+		if err != nil {
+			return "", err
+		}
+		choices = append(choices, translation)
+	}
+	return numberLines(choices), nil
+}
+
+// isSceneCut reports whether the screenshot differs enough from the previous one to be considered a new scene.
+func (a *App) isSceneCut(screenshot image.Image) bool {
+	if a.sceneCutThreshold <= 0 || a.lastScreenshot == nil {
+		return false
+	}
+	difference := frame.Difference(a.lastScreenshot, screenshot)
+	if difference < a.sceneCutThreshold {
+		return false
+	}
+	log.Info().Msgf("scene cut detected (difference %.2f)", difference)
+	return true
+}
+
+// translateImageFile runs the pipeline once on an image file instead of a captured window.
+func (a *App) translateImageFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode image %s: %w", path, err)
+	}
+
+	translation, _, err := a.process(frame.RGBA(img))
+	return translation, err
 }
 
 func (a *App) Update() error {
+	select {
+	case <-a.interrupted:
+		a.cancel()
+		return ebiten.Termination
+	default:
+	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
 		ebiten.SetWindowDecorated(!ebiten.IsWindowDecorated())
 	}
+	a.handleCopyKeys()
+	a.handleResetLanguageKey()
+	a.handlePauseKey()
+	a.handleCycleLanguageKey()
+	a.handleClick()
+	a.keepOnMonitor()
+	a.keepOnTop()
+	a.updateClickThrough()
+
+	if a.paused || a.isStarting() || a.isMoving() {
+		return nil
+	}
+	if !a.isTargetFocused() {
+		return nil
+	}
 
 	// Check if it's time to refresh
-	if !time.Now().After(a.lastUpdate.Add(a.refreshRate)) {
+	if !time.Now().After(a.lastUpdate.Add(a.getRefreshRate())) {
 		return nil
 	}
 	a.lastUpdate = time.Now()
 
+	// A refresh slower than the refresh rate makes the next ones skipped instead of running alongside it
+	if !a.startRefresh(a.refreshWithPolicy) {
+		log.Debug().Msg("previous refresh still running, skipping this one")
+	}
+	return nil
+}
+
+// startRefresh runs the refresh in the background, unless the previous one is still running. It reports whether the
+// refresh was started.
+func (a *App) startRefresh(refresh func()) bool {
+	select {
+	case a.refreshing <- struct{}{}:
+	default:
+		return false
+	}
 	go func() {
-		screenshot, err := a.screenshot(a.windowTitle)
-		if err != nil {
-			log.Fatal().Err(err).Send()
-		}
+		defer func() { <-a.refreshing }()
+		refresh()
+	}()
+	return true
+}
 
-		if a.debug { // Save screenshot to disk
-			f, err := os.Create(fmt.Sprintf("screenshot-%d.jpg", a.lastUpdate.UnixNano()))
-			if err != nil {
-				log.Fatal().Err(err).Send()
-			}
-			defer f.Close()
-			if err = jpeg.Encode(f, screenshot, &jpeg.Options{Quality: 85}); err != nil {
-				log.Fatal().Err(err).Send()
-			}
-		}
+// refresh captures the window and updates the subtitles.
+func (a *App) refresh() error {
+	if a.stdinLines != nil {
+		return a.refreshFromStdin()
+	}
 
-		text, err := a.annotate(screenshot)
-		if err != nil {
-			log.Fatal().Err(err).Send()
-		}
-		if text == a.lastText {
-			return
+	screenshot, err := a.screenshot()
+	if err != nil {
+		if a.saveOnError {
+			a.saveErrorReport(nil, err)
 		}
-		if text == "" {
-			a.subs = ""
-			return
+		return err
+	}
+
+	if a.debug && !a.annotateScreenshots { // Save screenshot to disk, annotated ones being saved once OCR is done
+		if err := saveScreenshot(fmt.Sprintf("screenshot-%d.jpg", a.lastUpdate.UnixNano()), screenshot); err != nil {
+			return err
 		}
+	}
 
-		translation, err := a.translator.Translate(text)
-		if err != nil {
-			log.Fatal().Err(err).Send()
+	subs, changed, err := a.process(screenshot)
+	if err != nil {
+		if a.saveOnError {
+			a.saveErrorReport(screenshot, err)
 		}
-		log.Info().Msgf("translated text: %s", translation)
+		return err
+	}
+	a.show(subs, changed)
+	return nil
+}
 
-		a.lastText = text
-		a.subs = translation
-	}()
+// show displays the new subtitles if they changed.
+func (a *App) show(subs string, changed bool) {
+	if a.autoRefreshRate != nil {
+		a.autoRefreshRate.observe(changed)
+	}
+	if changed {
+		source := a.getLastText()
+		a.setSubs(subs, source)
+		a.history.add(source, subs, time.Now())
+		a.updateTranscript(source, subs, time.Now())
+		a.displayedAt = time.Now()
+		a.updateLiveFile()
+	}
+}
 
-	return nil
+// getSubs returns the subtitles displayed and their source text.
+func (a *App) getSubs() (string, string) {
+	a.subsMu.Lock()
+	defer a.subsMu.Unlock()
+	return a.subs, a.subsSource
+}
+
+func (a *App) setSubs(subs, source string) {
+	a.subsMu.Lock()
+	defer a.subsMu.Unlock()
+	a.subs, a.subsSource = subs, source
+}
+
+// getLastText returns the last text translated.
+func (a *App) getLastText() string {
+	a.subsMu.Lock()
+	defer a.subsMu.Unlock()
+	return a.lastText
+}
+
+func (a *App) setLastText(text string) {
+	a.subsMu.Lock()
+	defer a.subsMu.Unlock()
+	a.lastText = text
+}
+
+// clearSubs hides the subtitles and forgets the last text, for it to be translated again.
+func (a *App) clearSubs() {
+	a.subsMu.Lock()
+	defer a.subsMu.Unlock()
+	a.subs, a.subsSource, a.lastText = "", "", ""
+}
+
+// refreshWithPolicy refreshes the subtitles and handles failures according to the on-error policy.
+func (a *App) refreshWithPolicy() {
+	err := a.refresh()
+	for attempt := 1; err != nil && a.onErrorPolicy == configuration.OnErrorRetry && attempt <= a.onErrorRetries; attempt++ {
+		log.Warn().Err(err).Msgf("refresh failed, retrying (%d/%d)", attempt, a.onErrorRetries)
+		err = a.refresh()
+	}
+	a.failure.set(err)
+	if err == nil {
+		return
+	}
+	if a.onErrorPolicy == configuration.OnErrorFatal {
+		log.Fatal().Err(err).Send()
+	}
+	log.Warn().Err(err).Msg("refresh failed, keeping the current subtitles")
+}
+
+func saveScreenshot(name string, screenshot image.Image) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return jpeg.Encode(f, screenshot, &jpeg.Options{Quality: 85})
 }
 
 func (a *App) Draw(screen *ebiten.Image) {
+	defer a.record(screen)
+
+	subs, _ := a.getSubs()
 	width, height := ebiten.WindowSize()
 	if ebiten.IsWindowDecorated() {
 		ebitenutil.DrawRect(screen, 0, 0, float64(width), float64(height), color.Black)
 		message := "Press T to toggle window"
-		if a.subs == "" {
+		if subs == "" {
 			message += "\n[no text detected]"
 		}
+		if a.paused {
+			message += "\n[paused]"
+		}
 		ebitenutil.DebugPrint(screen, message)
+		a.failure.draw(screen)
+	}
+
+	if a.statusLine {
+		a.drawStatusLine(screen, width, height)
+	}
+	if a.isStarting() {
+		a.drawCountdown(screen, width)
+		return
+	}
+	if a.offline {
+		a.drawOfflineIndicator(screen, height)
+	}
+	if a.confidence != nil {
+		a.confidence.draw(screen, width, height)
+	}
+	if a.ticker != nil {
+		a.ticker.draw(screen, subs, width, height)
+		return
 	}
 
-	if a.subs == "" {
+	// The translation of the block clicked replaces the subtitles
+	selected := ""
+	if a.selection != nil {
+		selected = a.selection.get()
+	}
+	if selected != "" {
+		subs = selected
+	}
+	if subs == "" {
+		return
+	}
+	if a.positions != nil && selected == "" && a.drawPositions(screen, width, height) {
 		return
 	}
 
-	var line, subtitles bytes.Buffer
-	for _, word := range strings.Fields(a.subs) {
-		bound := text.BoundString(a.subsFont, line.String()+word)
-		if bound.Dx() > width {
-			subtitles.WriteString(line.String())
-			subtitles.WriteString("\n")
-			line = bytes.Buffer{}
-		}
-		line.WriteString(word)
-		line.WriteString(" ")
+	subtitles := strings.Join(wrapText(a.subsFont, subs, int(float64(width)*a.maxWidth)), "\n")
+
+	// Outdated subtitles are dimmed and followed by an ellipsis until the new text is translated
+	stale := selected == "" && a.isStale()
+	fontColor, alpha := a.subsFontColor, float32(1)
+	if stale {
+		subtitles += staleMarker
+		fontColor, alpha = dim(fontColor), staleAlpha
 	}
-	subtitles.WriteString(line.String())
 
-	bound := text.BoundString(a.subsFont, subtitles.String())
+	bound := text.BoundString(a.subsFont, subtitles)
 	boxSize := image.Point{X: bound.Max.X, Y: bound.Dy() + a.subsFont.Metrics().Height.Round()}
 
 	x := 0
@@ -197,7 +805,25 @@ func (a *App) Draw(screen *ebiten.Image) {
 		x = (width - boxSize.X) / 2
 	}
 	ebitenutil.DrawRect(screen, float64(x), float64(0), float64(boxSize.X), float64(boxSize.Y), a.subsBackgroundColor)
-	text.Draw(screen, subtitles.String(), a.subsFont, x, a.subsFont.Metrics().Height.Round(), a.subsFontColor)
+	if a.gradient != nil {
+		a.drawGradientText(screen, subtitles, x, boxSize, alpha)
+		return
+	}
+	text.Draw(screen, subtitles, a.subsFont, x, a.subsFont.Metrics().Height.Round(), fontColor)
+}
+
+// newFace returns a face of the given size, falling back to the default size when it can't be created.
+func newFace(ttf *opentype.Font, size int) (font.Face, error) {
+	face, err := opentype.NewFace(ttf, &opentype.FaceOptions{
+		Size:    float64(size),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err == nil || size == configuration.DefaultFontSize {
+		return face, err
+	}
+	log.Warn().Err(err).Msgf("unable to load the font at size %d, using %d instead", size, configuration.DefaultFontSize)
+	return newFace(ttf, configuration.DefaultFontSize)
 }
 
 func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
@@ -205,6 +831,29 @@ func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 func main() {
+	debug := flag.Bool("d", false, "enable debug mode")
+	translateImage := flag.String("translate-image", "", "translate the text of an image file (PNG or JPEG), print it and exit")
+	runSetup := flag.Bool("setup", false, "interactively create the configuration file")
+	recordDir := flag.String("record", "", "save the overlay to this directory at every refresh")
+	listWindowsOnly := flag.Bool("list-windows", false, "list the windows that can be captured and exit")
+	video := flag.String("video", "", "translate the text of a video file, or a directory of frames, into an SRT file and exit")
+	stdin := flag.Bool("stdin", false, "translate the lines read from the standard input, one per refresh, instead of capturing the window")
+	transcriptFile := flag.String("transcript", "", "append the subtitles to this JSON Lines file as they're displayed, overriding transcript-file")
+	flag.Parse()
+
+	if *listWindowsOnly {
+		if _, err := listWindows(os.Stdout); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+		return
+	}
+	if *runSetup {
+		if err := setup(os.Stdin, os.Stdout); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+		return
+	}
+
 	// Read configuration
 	config, err := configuration.Read()
 	if err != nil {
@@ -212,35 +861,111 @@ func main() {
 		switch {
 		case errors.As(err, &configNotFound):
 			log.Info().Msg("Configuration file not found: Creating default configuration file")
-			if err = configuration.WriteDefault(); err != nil {
+			path, err := configuration.WriteDefault()
+			if err != nil {
 				log.Fatal().Err(err).Send()
 			}
-			log.Info().Msg("Default configuration file successfully created.")
+			log.Info().Msgf("Default configuration file created at %s. Please fill in these settings and run interpreter again:", path)
+			log.Info().Msg(`  window-title: part of the title of the window to translate`)
+			log.Info().Msg(`  translator.api: "google", with the GOOGLE_APPLICATION_CREDENTIALS environment variable set, "deepl" or "libretranslate"`)
+			log.Info().Msg(`  translator.authentication-key: your DeepL authentication key, for "deepl" only`)
+			log.Info().Msg(`  translator.endpoint: the URL of your LibreTranslate instance, for "libretranslate" only`)
+			log.Info().Msg("You can also run interpreter --setup to create it interactively, or set INTERPRETER_TRANSLATOR_API and INTERPRETER_WINDOW_TITLE to run without it.")
 			return
 		default:
 			log.Fatal().Err(err).Send()
 		}
 	}
-	debug := flag.Bool("d", false, "enable debug mode")
-	flag.Parse()
 	if *debug {
-		config.Debug = true
+		config.Debug.Enabled = true
+	}
+	if config.Debug.Enabled {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	if *translateImage != "" { // Keep stdout for the translation
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339})
 	}
 	log.Info().Msg(pp.Sprint(config))
 
-	// Vision
-	visionClient, err := vision.NewImageAnnotatorClient(context.Background())
+	// Vision, not needed when the text is read from the standard input
+	imageFormat, err := config.OCR.GetImageFormat()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	subsampling, err := config.OCR.GetJPEGSubsampling()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	scale, err := config.OCR.GetScale()
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
-	defer visionClient.Close()
+	var visionOCR ocr.OCR
+	if !*stdin {
+		latencyTarget := config.OCR.GetLatencyTarget()
+		var minScale float64
+		if latencyTarget > 0 {
+			if minScale, err = config.OCR.GetMinScale(); err != nil {
+				log.Fatal().Err(err).Send()
+			}
+		}
+		connectVision := func() (ocr.OCR, error) {
+			vision, err := ocr.NewVision(config.OCR.MaxImageSize, imageFormat, subsampling, scale)
+			if err != nil {
+				return nil, err
+			}
+			vision.AdaptScale(latencyTarget, minScale)
+			return vision, nil
+		}
+		if config.OnError.ReconnectAfter > 0 {
+			visionOCR, err = ocr.NewReconnecting(connectVision, config.OnError.ReconnectAfter)
+		} else {
+			visionOCR, err = connectVision()
+		}
+		if err != nil {
+			log.Fatal().Err(err).Send()
+		}
+		defer visionOCR.Close()
+	}
 
 	// Translator
 	translator, err := config.GetTranslator()
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
+	// The translators of the other target languages, switched to with the cycle-language key
+	cycleTranslators, err := config.GetCycleTranslators()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	translators := append([]translate.Translator{translator}, cycleTranslators...)
+	var targetSwitch *translate.Switch
+	if len(translators) > 1 {
+		targetSwitch = translate.NewSwitch(translators...)
+		translator = targetSwitch
+	}
 	defer translator.Close()
+	// Every target language is checked, not to fail when cycling to one later on
+	for _, translator := range translators {
+		if err := checkTranslator(translator); err != nil {
+			log.Fatal().Err(err).Msgf("unable to use the %s translator, please check your configuration", config.Translator.API)
+		}
+	}
+
+	compareTranslator, err := config.GetCompareTranslator()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if compareTranslator != nil {
+		if err := checkTranslator(compareTranslator); err != nil {
+			log.Fatal().Err(err).Msgf("unable to use the %s compare translator, please check your configuration", config.Translator.Compare.API)
+		}
+	}
+	compareName := ""
+	if compareTranslator != nil {
+		defer compareTranslator.Close()
+		compareName = config.Translator.Compare.API
+	}
 
 	// Font
 	fontColor, err := config.Subs.Font.GetColor()
@@ -248,31 +973,127 @@ func main() {
 		log.Fatal().Err(err).Send()
 	}
 
+	gradient, err := config.Subs.Font.GetGradient()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	gradientDirection, err := config.Subs.Font.GetGradientDirection()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
 	backgroundColor, err := config.Subs.Background.GetColor()
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
 
-	ttf, err := opentype.Parse(fonts.MPlus1pRegular_ttf)
+	blocklist, err := config.GetBlocklist()
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
-	fontFace, err := opentype.NewFace(ttf, &opentype.FaceOptions{
-		Size:    float64(config.Subs.Font.Size),
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
+
+	allowlist, err := config.GetAllowlist()
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
 
-	ebiten.SetWindowTitle("Interpreter")
-	ebiten.SetScreenTransparent(true)
-	ebiten.SetWindowFloating(true)
-	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	exportFormat, err := config.GetExportOnExit()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	mode, err := config.Subs.GetMode()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	pageSeparator, err := config.OCR.GetPageSeparator()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	languageHints, err := config.OCR.GetLanguageHints()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	style, err := config.Subs.GetStyle()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	layout, err := config.Subs.GetLayout()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	maxWidth, err := config.Subs.GetMaxWidth()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	clickThrough, err := config.Display.GetClickThroughModifier()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if clickThrough != "" && !clickThroughSupported {
+		log.Warn().Msgf("click-through is only supported on Windows, ignoring `display.click-through-modifier` %q", clickThrough)
+		clickThrough = ""
+	}
+
+	onErrorPolicy, err := config.OnError.GetPolicy()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	statusCorner, err := config.Subs.GetStatusCorner()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	copyTranslationKey, err := parseKey("keys.copy-translation", config.Keys.CopyTranslation)
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	copySourceKey, err := parseKey("keys.copy-source", config.Keys.CopySource)
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	resetLanguageKey, err := parseKey("keys.reset-language", config.Keys.ResetLanguage)
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	pauseKey, err := parseKey("keys.pause", config.Keys.Pause)
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	cycleLanguageKey, err := parseKey("keys.cycle-language", config.Keys.CycleLanguage)
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	normalize, err := config.GetNormalize()
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	ttf, err := opentype.Parse(fonts.MPlus1pRegular_ttf)
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	fontFace, err := languageFace(ttf, &config.Subs.Font, config.Translator.Target())
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	targets := append([]string{config.Translator.Target()}, config.Translator.Cycle...)
+	targetFaces := []font.Face{fontFace}
+	for _, target := range config.Translator.Cycle {
+		face, err := languageFace(ttf, &config.Subs.Font, target)
+		if err != nil {
+			log.Fatal().Err(err).Send()
+		}
+		targetFaces = append(targetFaces, face)
+	}
 
 	app := &App{
-		visionClient:        visionClient,
+		ocr:                 visionOCR,
 		translator:          translator,
 		subsFont:            fontFace,
 		subsFontColor:       fontColor,
@@ -280,9 +1101,165 @@ func main() {
 		windowTitle:         config.WindowTitle,
 		refreshRate:         config.GetRefreshRate(),
 		confidenceThreshold: config.ConfidenceThreshold,
-		debug:               config.Debug,
+		debug:               config.Debug.Enabled,
+		persist:             config.Subs.Persist,
+		sceneCutThreshold:   config.Subs.SceneCutThreshold,
+		mode:                mode,
+		stripCJKSpaces:      config.OCR.StripCJKSpaces,
+		monitor:             config.Display.Monitor,
+		maxWidth:            maxWidth,
+		clickThrough:        clickThrough,
+		onErrorPolicy:       onErrorPolicy,
+		onErrorRetries:      config.OnError.Retries,
+		useCache:            config.OnError.UseCache,
+		frameCache:          frame.NewCache(config.GetFrameCacheTTL()),
+		dedupThreshold:      config.Subs.DedupThreshold,
+		translatorName:      config.Translator.API,
+		statusLine:          config.Subs.StatusLine,
+		statusCorner:        statusCorner,
+		minRegion:           config.OCR.MinRegion,
+		scrollStitch:        config.OCR.ScrollStitch,
+		scrollMaxLength:     config.OCR.ScrollMaxLength,
+		incremental:         config.OCR.Incremental,
+		copyTranslationKey:  copyTranslationKey,
+		copySourceKey:       copySourceKey,
+		recordDir:           *recordDir,
+		focusGracePeriod:    config.GetFocusGracePeriod(),
+		compareTranslator:   compareTranslator,
+		compareName:         compareName,
+		languageLockAfter:   config.OCR.GetLockSourceLanguage(),
+		resetLanguageKey:    resetLanguageKey,
+		pauseKey:            pauseKey,
+		cycleLanguageKey:    cycleLanguageKey,
+		targets:             targets,
+		targetFaces:         targetFaces,
+		targetSwitch:        targetSwitch,
+		excludedZones:       config.OCR.GetExcludedZones(),
+		languageHints:       languageHints,
+		translationTimeout:  config.Translator.GetTimeout(),
+		autoTimeout:         config.Translator.IsAutoTimeout(),
+		scriptSwitching:     config.OCR.ScriptSwitching,
+		normalize:           normalize,
+		alwaysOnTop:         config.Display.AlwaysOnTop,
+		diffTranslation:     config.OCR.DiffTranslation,
+		diffMinPrefix:       config.OCR.DiffMinPrefix,
+		gradient:            gradient,
+		gradientDirection:   gradientDirection,
+		staleThreshold:      config.Subs.GetStaleIndicator(),
+		dehyphenate:         config.OCR.Dehyphenate,
+		paragraphs:          config.Translator.Paragraphs,
+		blocklist:           blocklist,
+		allowlist:           allowlist,
+		liveFile:            config.Subs.LiveFile,
+		emptyTolerance:      config.OCR.EmptyTolerance,
+		groupByColor:        config.Subs.GroupByColor,
+		saveOnError:         config.Capture.SaveOnError,
+		annotateScreenshots: config.Debug.AnnotateScreenshots,
+		skipTrivial:         config.Translator.SkipTrivial,
+		pageSeparator:       pageSeparator,
+		splitPages:          config.OCR.SplitPages,
+		pauseWhileMoving:    config.Display.PauseWhileMoving,
+		startAt:             time.Now().Add(config.GetStartupDelay()),
+		history:             newHistory(),
+		interrupted:         make(chan os.Signal, 1),
+		refreshing:          make(chan struct{}, 1),
+	}
+	app.ctx, app.cancel = context.WithCancel(context.Background())
+	if *transcriptFile == "" {
+		*transcriptFile = config.TranscriptFile
+	}
+	if *transcriptFile != "" {
+		if app.transcript, err = openTranscript(*transcriptFile); err != nil {
+			log.Fatal().Err(err).Msg("unable to open the transcript")
+		}
+	}
+	if _, ok := translate.AsUsageReporter(translator); ok && app.statusLine {
+		go app.pollUsage(translator)
+	}
+	if style == configuration.StyleTicker {
+		app.ticker = &ticker{face: fontFace, speed: config.Subs.TickerSpeed, fontColor: fontColor, backgroundColor: backgroundColor}
+	}
+	if config.Subs.ClickToSelect {
+		app.selection = &blockSelection{}
+	}
+	if layout == configuration.LayoutPositional {
+		app.positions = &positionedSubs{smoothing: config.Subs.GetPositionSmoothing()}
+	}
+	if app.paragraphs || app.positions != nil {
+		if app.cachedTranslator, err = newCachedTranslator(translators); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	}
+	if config.Subs.ShowConfidence {
+		confidenceFace, err := newFace(ttf, confidenceFontSize)
+		if err != nil {
+			log.Fatal().Err(err).Send()
+		}
+		app.confidence = &confidenceOverlay{face: confidenceFace}
+	}
+	if config.IsAutoRefreshRate() {
+		app.autoRefreshRate = newAdaptiveRefreshRate(config.GetRefreshRateBounds())
 	}
+
+	// Don't leave the subtitles of the previous session in the live file
+	app.updateLiveFile()
+
+	if *stdin {
+		app.stdinLines = readLines(os.Stdin)
+		app.focusGracePeriod = 0
+	}
+
+	if *recordDir != "" {
+		if err := os.MkdirAll(*recordDir, 0755); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	}
+
+	if *video != "" {
+		name, err := app.translateVideo(*video)
+		if err != nil {
+			log.Fatal().Err(err).Send()
+		}
+		log.Info().Msgf("subtitles written to %s", name)
+		return
+	}
+
+	if *translateImage != "" {
+		translation, err := app.translateImageFile(*translateImage)
+		if err != nil {
+			log.Fatal().Err(err).Send()
+		}
+		fmt.Println(translation)
+		return
+	}
+
+	// The window to capture, only needed when the text isn't read from the standard input
+	if !*stdin {
+		if app.capturer, err = newCapturer(config); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	}
+
+	ebiten.SetWindowTitle(windowTitle)
+	ebiten.SetScreenTransparent(config.Display.Transparent)
+	ebiten.SetWindowFloating(config.Display.Floating)
+	ebiten.SetVsyncEnabled(config.Display.VSync)
+	ebiten.SetWindowDecorated(config.Display.Decorated)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	// Exit gracefully on Ctrl+C as when the window is closed
+	signal.Notify(app.interrupted, os.Interrupt, syscall.SIGTERM)
 	if err := ebiten.RunGame(app); err != nil {
 		log.Fatal().Err(err).Send()
 	}
+	app.cancel() // Stop the OCR and translations still running
+	app.closeTranscript()
+	app.stats.writeSummary(os.Stdout, config.Translator.API)
+
+	if exportFormat != "" {
+		name, err := app.history.exportFile(exportFormat)
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to export the session history")
+		}
+		log.Info().Msgf("session history exported to %s", name)
+	}
 }
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package main
+
+// clickThroughSupported is false outside of Windows: keys are only seen while the subtitles window has the focus,
+// which a click-through window never gets back.
+const clickThroughSupported = false
+
+// isModifierPressed reports whether the modifier is held. It's only supported on Windows.
+func isModifierPressed(modifier string) bool {
+	return false
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"golang.org/x/sys/windows"
+)
+
+// clickThroughSupported is true as the modifier state is read even when another window has the focus.
+const clickThroughSupported = true
+
+var getAsyncKeyState = windows.NewLazySystemDLL("user32.dll").NewProc("GetAsyncKeyState")
+
+var virtualKeys = map[string]uintptr{
+	configuration.ModifierShift:   0x10, // VK_SHIFT
+	configuration.ModifierControl: 0x11, // VK_CONTROL
+	configuration.ModifierAlt:     0x12, // VK_MENU
+}
+
+// isModifierPressed reports whether the modifier is held, even when another window has the focus.
+func isModifierPressed(modifier string) bool {
+	state, _, _ := getAsyncKeyState.Call(virtualKeys[modifier])
+	return state&0x8000 != 0
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/image/font/basicfont"
+)
+
+const (
+	offlineIndicator = "offline (cached)"
+	offlineMargin    = 4
+)
+
+// fallBackToCache displays the translation the text got earlier in the session when the translators fail, or keeps the
+// current subtitles when the text was never translated, in which case it is translated again on the next refresh.
+func (a *App) fallBackToCache(text string, err error) (string, bool, error) {
+	a.offline = true
+	translation, ok := a.history.lookup(text)
+	if !ok {
+		log.Warn().Err(err).Msg("unable to translate, keeping the current subtitles")
+		return "", false, nil
+	}
+	log.Warn().Err(err).Msgf("unable to translate, using the translation cached earlier: %s", translation)
+	a.setLastText(text)
+	a.pendingSince = time.Time{}
+	return translation, true, nil
+}
+
+// drawOfflineIndicator draws the offline indicator in the bottom left corner of the window.
+func (a *App) drawOfflineIndicator(screen *ebiten.Image, height int) {
+	face := basicfont.Face7x13
+	text.Draw(screen, offlineIndicator, face, offlineMargin, height-face.Descent-offlineMargin, a.subsFontColor)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+// translatePages translates each page of the text separately, one translation per line.
+func (a *App) translatePages(ctx context.Context, text string) (string, error) {
+	var translations []string
+	for _, page := range strings.Split(text, a.pageSeparator) {
+		if strings.TrimSpace(page) == "" {
+			continue
+		}
+		translation, err := a.translator.Translate(ctx, page)
+		if err != nil {
+			return "", err
+		}
+		translations = append(translations, translation)
+	}
+	return strings.Join(translations, "\n"), nil
+}
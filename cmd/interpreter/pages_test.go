@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// loadAnnotation reads a Vision annotation fixture from the testdata folder.
+func loadAnnotation(t *testing.T, name string) *visionpb.TextAnnotation {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var annotation visionpb.TextAnnotation
+	if err := protojson.Unmarshal(data, &annotation); err != nil {
+		t.Fatal(err)
+	}
+	return &annotation
+}
+
+func TestFilterTextByConfidencePages(t *testing.T) {
+	annotation := loadAnnotation(t, "pages.json")
+	threshold := configuration.ConfidenceThreshold{configuration.DefaultLanguage: 0.9}
+	tests := []struct {
+		name                     string
+		separator, pageSeparator string
+		want                     string
+	}{
+		{"joined", "", "", "こんにちは。元気ですか？さようなら。"},
+		{"line per page", "", "\n", "こんにちは。元気ですか？\nさようなら。"},
+		{"marker", "", " | ", "こんにちは。元気ですか？ | さようなら。"},
+		{"paragraphs", "\n", "\n\n", "こんにちは。\n元気ですか？\n\nさようなら。"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if text := filterTextByConfidence(annotation, threshold, false, test.separator, test.pageSeparator); text != test.want {
+				t.Errorf("filterTextByConfidence() = %q, want %q", text, test.want)
+			}
+		})
+	}
+}
+
+func TestTranslatePages(t *testing.T) {
+	fake := &translatetest.Fake{Prefix: "en:"}
+	a := &App{translator: fake, pageSeparator: " | "}
+
+	translation, err := a.translatePages(context.Background(), "こんにちは。 | さようなら。 |  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "en:こんにちは。\nen:さようなら。"; translation != want {
+		t.Errorf("translatePages() = %q, want %q", translation, want)
+	}
+	if calls, want := fake.Calls(), []string{"こんにちは。", "さようなら。"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("translator called with %q, want %q", calls, want)
+	}
+}
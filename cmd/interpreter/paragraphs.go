@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bquenin/interpreter/internal/translate"
+)
+
+// cacheSize is the number of paragraph translations remembered in paragraphs mode, or of block translations in
+// positional layout.
+const cacheSize = 1000
+
+// newCachedTranslator returns a translator caching the translations of each target language, not to display the
+// translations to the previous one.
+func newCachedTranslator(translators []translate.Translator) (translate.Translator, error) {
+	cachedTranslators := make([]translate.Translator, 0, len(translators))
+	for _, translator := range translators {
+		cached, err := translate.NewCached(translator, cacheSize)
+		if err != nil {
+			return nil, err
+		}
+		cachedTranslators = append(cachedTranslators, cached)
+	}
+	if len(cachedTranslators) == 1 {
+		return cachedTranslators[0], nil
+	}
+	return translate.NewSwitch(cachedTranslators...), nil
+}
+
+// paragraphSeparator returns what the paragraphs detected by OCR are joined with in the extracted text.
+func (a *App) paragraphSeparator() string {
+	if a.paragraphs {
+		return "\n"
+	}
+	return ""
+}
+
+// translateParagraphs translates each paragraph separately, so that a small OCR change only translates the paragraph
+// it belongs to again, the others coming from the cache.
+func (a *App) translateParagraphs(ctx context.Context, text string) (string, error) {
+	paragraphs := strings.Split(text, "\n")
+	translations := make([]string, 0, len(paragraphs))
+	for _, paragraph := range paragraphs {
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		translation, err := a.cachedTranslator.Translate(ctx, paragraph)
+		if err != nil {
+			return "", err
+		}
+		translations = append(translations, translation)
+	}
+	return strings.Join(translations, "\n"), nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/bquenin/interpreter/internal/cleanup"
+	"github.com/bquenin/interpreter/internal/ocr"
+	"github.com/bquenin/interpreter/internal/ocr/ocrtest"
+	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+	"github.com/spf13/viper"
+)
+
+// newTestApp returns an app running its pipeline on the given OCR and translator, with the default settings.
+func newTestApp(t *testing.T, detector ocr.OCR, translator translate.Translator) *App {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return &App{
+		ctx:                 ctx,
+		cancel:              cancel,
+		ocr:                 detector,
+		translator:          translator,
+		confidenceThreshold: configuration.ConfidenceThreshold{configuration.DefaultLanguage: 0.9},
+		mode:                configuration.ModeSubtitles,
+		normalize:           cleanup.NormalizeNone,
+		emptyTolerance:      1,
+		history:             newHistory(),
+		refreshing:          make(chan struct{}, 1),
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "dialogue.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detector := &ocrtest.Fake{Annotation: loadAnnotation(t, "dialogue.json")}
+	translator := &translatetest.Fake{Prefix: "fr:"}
+	a := newTestApp(t, detector, translator)
+	a.minRegion = 0.01 // Drops the HP counter
+	a.normalize = cleanup.NormalizeNFKC
+
+	// The word below the confidence threshold is filtered out, the full-width exclamation mark normalized
+	subs, err := a.translateImageFile(filepath.Join("testdata", "dialogue.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.TrimSuffix(string(golden), "\n"); subs != want {
+		t.Errorf("subtitles = %q, want %q", subs, want)
+	}
+
+	// The same text isn't translated again, the subtitles being left unchanged
+	if _, changed, err := a.process(a.lastScreenshot); err != nil || changed {
+		t.Errorf("process() of the same frame changed the subtitles: %t, %v", changed, err)
+	}
+	if calls, want := translator.Calls(), []string{"勇者よ、よくぞ来た!城は北にある。"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("translator called with %q, want %q", calls, want)
+	}
+	if detections := len(detector.Options()); detections != 2 {
+		t.Errorf("%d detections, want 2", detections)
+	}
+}
+
+func TestPipelineNoText(t *testing.T) {
+	translator := &translatetest.Fake{Prefix: "fr:"}
+	a := newTestApp(t, &ocrtest.Fake{}, translator)
+
+	if _, err := a.translateImageFile(filepath.Join("testdata", "dialogue.png")); err != nil {
+		t.Fatal(err)
+	}
+	if calls := translator.Calls(); len(calls) != 0 {
+		t.Errorf("translator called with %q, want no translation without text", calls)
+	}
+}
+
+// readEnvironmentConfiguration reads the configuration from the environment only, as on a demo or CI run.
+func readEnvironmentConfiguration(t *testing.T, env map[string]string) *configuration.Configuration {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		viper.Reset()
+	})
+	t.Setenv("HOME", dir)
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	viper.Reset()
+	config, err := configuration.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestPipelineFromEnvironment(t *testing.T) {
+	detector := &ocrtest.Fake{Annotation: loadAnnotation(t, "dialogue.json")}
+	frame, err := filepath.Abs(filepath.Join("testdata", "dialogue.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := readEnvironmentConfiguration(t, map[string]string{"INTERPRETER_TRANSLATOR_API": "none", "INTERPRETER_WINDOW_TITLE": ""})
+
+	// Without a window title, only the live capture can't run
+	if _, err := newCapturer(config); err == nil {
+		t.Error("newCapturer() succeeded without a window title")
+	}
+	translator, err := config.GetTranslator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newTestApp(t, detector, translator)
+	if _, err := a.translateImageFile(frame); err != nil {
+		t.Fatalf("translateImageFile() = %v, want the image translated without a window title", err)
+	}
+}
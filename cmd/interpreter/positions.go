@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"image"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bquenin/interpreter/internal/cleanup"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// positionedMinWidth is the minimum width of the translations as a fraction of the window width, translations being
+// often longer than the text of narrow blocks.
+const positionedMinWidth = 0.25
+
+// positionedSubs holds the translation of each block of the last frame, drawn over the block in positional layout.
+// With smoothing, the translations move to the new position of their block over the smoothing duration rather than
+// jumping to it.
+type positionedSubs struct {
+	mu        sync.Mutex
+	blocks    []block // The text of the blocks being their translation
+	frameSize image.Point
+	smoothing time.Duration
+	from      []image.Rectangle // The positions the translations move from, the block of the same index on the previous frame
+	movedAt   time.Time
+	pending   []block // The translations of the blocks not accepted yet, only used by the pipeline
+}
+
+func (p *positionedSubs) set(blocks []block, frameSize image.Point, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	from := make([]image.Rectangle, len(blocks))
+	for i, b := range blocks {
+		from[i] = b.bounds
+		if i < len(p.blocks) && frameSize == p.frameSize {
+			from[i] = p.position(i, now) // From where it's currently drawn, for a translation still moving
+		}
+	}
+	p.blocks, p.frameSize, p.from, p.movedAt = blocks, frameSize, from, now
+}
+
+// get returns the translations and the positions they're drawn at.
+func (p *positionedSubs) get(now time.Time) ([]block, image.Point) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	blocks := make([]block, len(p.blocks))
+	for i, b := range p.blocks {
+		blocks[i] = block{text: b.text, bounds: p.position(i, now), speaker: b.speaker}
+	}
+	return blocks, p.frameSize
+}
+
+// position returns where the translation of the block is drawn, on its way from its previous position.
+func (p *positionedSubs) position(i int, now time.Time) image.Rectangle {
+	elapsed := now.Sub(p.movedAt)
+	if p.smoothing <= 0 || elapsed >= p.smoothing {
+		return p.blocks[i].bounds
+	}
+	return lerpRect(p.from[i], p.blocks[i].bounds, float64(elapsed)/float64(p.smoothing))
+}
+
+// lerpRect returns the rectangle at the fraction t of the way from a to b.
+func lerpRect(a, b image.Rectangle, t float64) image.Rectangle {
+	lerp := func(from, to int) int {
+		return from + int(math.Round(float64(to-from)*t))
+	}
+	return image.Rect(lerp(a.Min.X, b.Min.X), lerp(a.Min.Y, b.Min.Y), lerp(a.Max.X, b.Max.X), lerp(a.Max.Y, b.Max.Y))
+}
+
+// translatePositions translates each block separately, for the translations to be drawn at the position of their block
+// once the subtitles are accepted. The translations are joined one per line for the history and the live file.
+func (a *App) translatePositions(ctx context.Context, blocks []block) (string, error) {
+	translated := make([]block, 0, len(blocks))
+	translations := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		translation, err := a.cachedTranslator.Translate(ctx, b.text)
+		if err != nil {
+			return "", err
+		}
+		translated = append(translated, block{text: translation, bounds: b.bounds})
+		translations = append(translations, translation)
+	}
+	a.positions.pending = translated
+	return strings.Join(translations, "\n"), nil
+}
+
+// acceptPositions draws the pending translations, normalized as the subtitles, over the blocks of the last frame.
+// Without a frame or the bounds of the blocks, as for the text read from the standard input, the subtitles are drawn
+// in the box layout instead.
+func (a *App) acceptPositions() {
+	pending := a.positions.pending
+	a.positions.pending = nil
+	if a.lastScreenshot == nil {
+		a.positions.set(nil, image.Point{}, time.Now())
+		return
+	}
+	blocks := make([]block, 0, len(pending))
+	for _, b := range pending {
+		if b.bounds.Empty() {
+			a.positions.set(nil, image.Point{}, time.Now())
+			return
+		}
+		blocks = append(blocks, block{text: cleanup.Normalize(b.text, a.normalize), bounds: b.bounds})
+	}
+	a.positions.set(blocks, a.lastScreenshot.Bounds().Size(), time.Now())
+}
+
+// drawPositions draws each translation over its block, the window being assumed to cover the captured frame.
+// It returns false when there are no positions to draw the subtitles at.
+func (a *App) drawPositions(screen *ebiten.Image, width, height int) bool {
+	blocks, frameSize := a.positions.get(time.Now())
+	if len(blocks) == 0 || frameSize.X <= 0 || frameSize.Y <= 0 {
+		return false
+	}
+	lineHeight := a.subsFont.Metrics().Height.Round()
+	for _, b := range blocks {
+		x, y := b.bounds.Min.X*width/frameSize.X, b.bounds.Min.Y*height/frameSize.Y
+		maxWidth := b.bounds.Dx() * width / frameSize.X
+		if minWidth := int(float64(width) * positionedMinWidth); maxWidth < minWidth {
+			maxWidth = minWidth
+		}
+		translation := strings.Join(wrapText(a.subsFont, b.text, maxWidth), "\n")
+		bound := text.BoundString(a.subsFont, translation)
+		boxSize := image.Point{X: bound.Max.X, Y: bound.Dy() + lineHeight}
+
+		// Keep the translations of the blocks near the edges inside the window
+		if x+boxSize.X > width {
+			x = width - boxSize.X
+		}
+		if y+boxSize.Y > height {
+			y = height - boxSize.Y
+		}
+		if x < 0 {
+			x = 0
+		}
+		if y < 0 {
+			y = 0
+		}
+		ebitenutil.DrawRect(screen, float64(x), float64(y), float64(boxSize.X), float64(boxSize.Y), a.subsBackgroundColor)
+		text.Draw(screen, translation, a.subsFont, x, y+lineHeight, a.subsFontColor)
+	}
+	return true
+}
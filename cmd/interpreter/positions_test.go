@@ -0,0 +1,167 @@
+package main
+
+import (
+	"image"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bquenin/interpreter/internal/cleanup"
+	"github.com/bquenin/interpreter/internal/ocr"
+	"github.com/bquenin/interpreter/internal/ocr/ocrtest"
+	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+// newPositionalApp returns a test app in positional layout.
+func newPositionalApp(t *testing.T, detector ocr.OCR, translator translate.Translator) *App {
+	t.Helper()
+	a := newTestApp(t, detector, translator)
+	a.positions = &positionedSubs{}
+	var err error
+	if a.cachedTranslator, err = newCachedTranslator([]translate.Translator{translator}); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestPositionsFromFrame(t *testing.T) {
+	a := newPositionalApp(t, &ocrtest.Fake{Annotation: loadAnnotation(t, "dialogue.json")}, &translatetest.Fake{Prefix: "ＦＲ:"})
+	a.minRegion = 0.01
+	a.normalize = cleanup.NormalizeNFKC
+
+	subs, err := a.translateImageFile(filepath.Join("testdata", "dialogue.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks, frameSize := a.positions.get(time.Now())
+	if frameSize != a.lastScreenshot.Bounds().Size() {
+		t.Errorf("frame size = %v, want %v", frameSize, a.lastScreenshot.Bounds().Size())
+	}
+	if len(blocks) == 0 {
+		t.Fatal("no positioned translations")
+	}
+	translations := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if b.bounds.Empty() {
+			t.Errorf("translation %q without bounds", b.text)
+		}
+		translations = append(translations, b.text)
+	}
+	// The translations are normalized as the subtitles
+	if joined := strings.Join(translations, "\n"); joined != subs || !strings.HasPrefix(joined, "FR:") {
+		t.Errorf("positioned translations = %q, want the subtitles %q", joined, subs)
+	}
+}
+
+func TestPositionsRejected(t *testing.T) {
+	a := newPositionalApp(t, &ocrtest.Fake{}, &translatetest.Fake{Prefix: "fr:"})
+	a.lastScreenshot = image.NewRGBA(image.Rect(0, 0, 320, 180))
+	a.dedupThreshold = 0.8
+	hello := []block{{text: "Hello there, how are you", bounds: image.Rect(10, 10, 100, 30)}}
+
+	subs, changed, err := a.processText(hello[0].text, hello, false)
+	if err != nil || !changed {
+		t.Fatalf("processText() = %t, %v, want the subtitles changed", changed, err)
+	}
+	a.show(subs, changed)
+
+	// A translation similar to the subtitles is rejected, the positions being left unchanged as the subtitles
+	similar := []block{{text: "Hello there, how are you?", bounds: image.Rect(50, 50, 200, 80)}}
+	if _, changed, err := a.processText(similar[0].text, similar, false); err != nil || changed {
+		t.Fatalf("processText() = %t, %v, want the translation rejected", changed, err)
+	}
+	if blocks, _ := a.positions.get(time.Now()); len(blocks) != 1 || blocks[0].bounds != hello[0].bounds {
+		t.Errorf("positions = %v, want the ones of the displayed subtitles", blocks)
+	}
+}
+
+func TestPositionsCached(t *testing.T) {
+	translator := &translatetest.Fake{Prefix: "fr:"}
+	a := newPositionalApp(t, &ocrtest.Fake{}, translator)
+	a.lastScreenshot = image.NewRGBA(image.Rect(0, 0, 320, 180))
+	name, first, second := image.Rect(10, 10, 60, 20), image.Rect(10, 50, 300, 80), image.Rect(10, 50, 300, 90)
+
+	for _, blocks := range [][]block{
+		{{text: "Alice", bounds: name}, {text: "Hello.", bounds: first}},
+		{{text: "Alice", bounds: name}, {text: "Goodbye.", bounds: second}},
+	} {
+		if _, _, err := a.processText(joinBlocks(blocks), blocks, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The name of the speaker, unchanged, isn't translated again
+	if calls, want := translator.Calls(), []string{"Alice", "Hello.", "Goodbye."}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("translator called with %q, want %q", calls, want)
+	}
+}
+
+func TestPositionsFromStdin(t *testing.T) {
+	a := newPositionalApp(t, &ocrtest.Fake{}, &translatetest.Fake{Prefix: "fr:"})
+	lines := make(chan string, 1)
+	lines <- "Hello"
+	a.stdinLines = lines
+
+	// Without a frame, the subtitles are drawn in the box layout
+	if err := a.refreshFromStdin(); err != nil {
+		t.Fatal(err)
+	}
+	if subs, _ := a.getSubs(); subs != "fr:Hello" {
+		t.Errorf("subtitles = %q, want %q", subs, "fr:Hello")
+	}
+	if blocks, frameSize := a.positions.get(time.Now()); len(blocks) != 0 || frameSize != (image.Point{}) {
+		t.Errorf("positions = %v in %v, want none", blocks, frameSize)
+	}
+}
+
+func TestPositionsSmoothing(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	frameSize := image.Pt(320, 180)
+	box := image.Rect(10, 100, 210, 140)
+	moved := box.Add(image.Pt(20, -10))
+
+	tests := []struct {
+		name      string
+		smoothing time.Duration
+		frameSize image.Point
+		elapsed   time.Duration
+		want      image.Rectangle
+	}{
+		{"moving", 100 * time.Millisecond, frameSize, 0, box},
+		{"halfway", 100 * time.Millisecond, frameSize, 50 * time.Millisecond, image.Rect(20, 95, 220, 135)},
+		{"arrived", 100 * time.Millisecond, frameSize, 100 * time.Millisecond, moved},
+		{"without smoothing", 0, frameSize, 0, moved},
+		{"new frame size", 100 * time.Millisecond, image.Pt(640, 360), 0, moved},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &positionedSubs{smoothing: test.smoothing}
+			p.set([]block{{text: "Hello", bounds: box}}, frameSize, start)
+			p.set([]block{{text: "Hello", bounds: moved}, {text: "New", bounds: box}}, test.frameSize, start)
+
+			blocks, _ := p.get(start.Add(test.elapsed))
+			if blocks[0].bounds != test.want {
+				t.Errorf("translation drawn at %v, want %v", blocks[0].bounds, test.want)
+			}
+			// A new block is drawn at its position right away
+			if blocks[1].bounds != box {
+				t.Errorf("new translation drawn at %v, want %v", blocks[1].bounds, box)
+			}
+		})
+	}
+}
+
+func TestPositionsSmoothingInterrupted(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := &positionedSubs{smoothing: 100 * time.Millisecond}
+	p.set([]block{{bounds: image.Rect(0, 0, 100, 20)}}, image.Pt(320, 180), start)
+	p.set([]block{{bounds: image.Rect(100, 0, 200, 20)}}, image.Pt(320, 180), start)
+
+	// Moving again halfway, the translation leaves from where it's drawn rather than jumping back
+	p.set([]block{{bounds: image.Rect(0, 0, 100, 20)}}, image.Pt(320, 180), start.Add(50*time.Millisecond))
+	if blocks, _ := p.get(start.Add(50 * time.Millisecond)); blocks[0].bounds != image.Rect(50, 0, 150, 20) {
+		t.Errorf("translation drawn at %v, want %v", blocks[0].bounds, image.Rect(50, 0, 150, 20))
+	}
+}
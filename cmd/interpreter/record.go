@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// record saves the composited overlay to the record directory, once per refresh.
+// Pixels are read during Draw but encoded in the background to keep rendering smooth.
+func (a *App) record(screen *ebiten.Image) {
+	if a.recordDir == "" || time.Since(a.lastRecord) < a.getRefreshRate() {
+		return
+	}
+	a.lastRecord = time.Now()
+
+	bounds := screen.Bounds()
+	frame := image.NewRGBA(bounds)
+	screen.ReadPixels(frame.Pix)
+
+	name := filepath.Join(a.recordDir, fmt.Sprintf("overlay-%d.png", a.lastRecord.UnixNano()))
+	go func() {
+		if err := savePNG(name, frame); err != nil {
+			log.Warn().Err(err).Msg("unable to record overlay")
+		}
+	}()
+}
+
+func savePNG(name string, img image.Image) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// adaptiveRefreshRate tunes the refresh rate to how often the detected text changes, within bounds.
+type adaptiveRefreshRate struct {
+	mu      sync.Mutex
+	min     time.Duration
+	max     time.Duration
+	current time.Duration
+	changes []time.Time
+}
+
+// changesKept is how many text changes are used to estimate the change frequency.
+const changesKept = 5
+
+func newAdaptiveRefreshRate(min, max time.Duration) *adaptiveRefreshRate {
+	return &adaptiveRefreshRate{min: min, max: max, current: min}
+}
+
+func (r *adaptiveRefreshRate) get() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// observe records the outcome of a refresh. Text changes bring the rate to twice the change frequency,
+// refreshes without change slowly relax it towards the maximum.
+func (r *adaptiveRefreshRate) observe(changed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !changed {
+		r.current = clampDuration(r.current*11/10, r.min, r.max)
+		return
+	}
+
+	r.changes = append(r.changes, time.Now())
+	if len(r.changes) > changesKept {
+		r.changes = r.changes[1:]
+	}
+	if len(r.changes) < 2 {
+		return
+	}
+	averageGap := r.changes[len(r.changes)-1].Sub(r.changes[0]) / time.Duration(len(r.changes)-1)
+	r.current = clampDuration(averageGap/2, r.min, r.max)
+}
+
+func clampDuration(d, min, max time.Duration) time.Duration {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// getRefreshRate returns how often a screenshot is taken.
+func (a *App) getRefreshRate() time.Duration {
+	if a.autoRefreshRate != nil {
+		return a.autoRefreshRate.get()
+	}
+	return a.refreshRate
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bquenin/interpreter/internal/ocr/ocrtest"
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+// waitFor waits for the condition to be met, failing the test after a few seconds.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !condition(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+	}
+}
+
+func TestStartRefreshSkipsWhileRunning(t *testing.T) {
+	release := make(chan struct{})
+	translator := &translatetest.Fake{Prefix: "en:", Release: release}
+	a := newTestApp(t, &ocrtest.Fake{}, translator)
+	lines := make(chan string, 3)
+	lines <- "一"
+	lines <- "二"
+	lines <- "三"
+	a.stdinLines = lines
+
+	if !a.startRefresh(a.refreshWithPolicy) {
+		t.Fatal("first refresh not started")
+	}
+	waitFor(t, func() bool { return len(translator.Calls()) == 1 })
+
+	// The translation of the first line is blocked: the next refreshes are skipped rather than piling up
+	for i := 0; i < 3; i++ {
+		if a.startRefresh(a.refreshWithPolicy) {
+			t.Fatal("refresh started while the previous one is still running")
+		}
+	}
+
+	close(release)
+	waitFor(t, func() bool { return len(a.refreshing) == 0 })
+	if subs, _ := a.getSubs(); subs != "en:一" {
+		t.Errorf("subtitles = %q, want %q", subs, "en:一")
+	}
+
+	if !a.startRefresh(a.refreshWithPolicy) {
+		t.Fatal("refresh not started once the previous one is done")
+	}
+	waitFor(t, func() bool { return len(a.refreshing) == 0 })
+	if calls, want := translator.Calls(), []string{"一", "二"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("translator called with %q, want %q", calls, want)
+	}
+	if inFlight := translator.MaxInFlight(); inFlight != 1 {
+		t.Errorf("%d translations in flight at once, want 1", inFlight)
+	}
+}
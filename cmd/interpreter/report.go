@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errorReportDir is where the frames and details of the failed refreshes are saved with capture.save-on-error.
+const errorReportDir = "errors"
+
+// stageError is an error of a pipeline stage, along with the text being processed when it occurred.
+type stageError struct {
+	stage string
+	text  string
+	err   error
+}
+
+func (e *stageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stageError) Unwrap() error {
+	return e.err
+}
+
+// saveErrorReport saves the frame of a failed refresh, if any, and a sidecar file with the stage that failed, the
+// error and the text extracted to a folder of the day.
+func (a *App) saveErrorReport(screenshot image.Image, err error) {
+	now := time.Now()
+	dir := filepath.Join(errorReportDir, now.Format("2006-01-02"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Warn().Err(err).Msg("unable to create the error report folder")
+		return
+	}
+
+	stage, text := "unknown", ""
+	var failure *stageError
+	if errors.As(err, &failure) {
+		stage, text = failure.stage, failure.text
+	}
+	name := filepath.Join(dir, fmt.Sprintf("%s-%s", now.Format("150405.000"), stage))
+	if screenshot != nil {
+		if err := saveScreenshot(name+".jpg", screenshot); err != nil {
+			log.Warn().Err(err).Msg("unable to save the frame of the error report")
+		}
+	}
+	details := fmt.Sprintf("time: %s\nstage: %s\nerror: %v\ntext: %s\n", now.Format(time.RFC3339), stage, err, text)
+	if err := os.WriteFile(name+".txt", []byte(details), 0644); err != nil {
+		log.Warn().Err(err).Msg("unable to save the error report")
+		return
+	}
+	log.Info().Msgf("error report saved to %s.txt", name)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"image"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/rs/zerolog/log"
+)
+
+// blockSelection holds the blocks of the last frame, for clicks to be hit-tested against, and the translation of the
+// block clicked, displayed instead of the subtitles until a click lands outside of any block.
+type blockSelection struct {
+	mu          sync.Mutex
+	blocks      []block
+	frameSize   image.Point
+	translation string
+}
+
+func (s *blockSelection) setBlocks(blocks []block, frameSize image.Point) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks, s.frameSize = blocks, frameSize
+}
+
+// blockAt returns the block under the given window position, the window being assumed to cover the captured frame.
+func (s *blockSelection) blockAt(position, windowSize image.Point) (block, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if windowSize.X <= 0 || windowSize.Y <= 0 {
+		return block{}, false
+	}
+	p := image.Pt(position.X*s.frameSize.X/windowSize.X, position.Y*s.frameSize.Y/windowSize.Y)
+	for _, b := range s.blocks {
+		if p.In(b.bounds) {
+			return b, true
+		}
+	}
+	return block{}, false
+}
+
+func (s *blockSelection) setTranslation(translation string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.translation = translation
+}
+
+func (s *blockSelection) get() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.translation
+}
+
+// handleClick translates the block clicked in the window, or goes back to the subtitles when no block is clicked.
+func (a *App) handleClick() {
+	if a.selection == nil || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	x, y := ebiten.CursorPosition()
+	width, height := ebiten.WindowSize()
+	b, ok := a.selection.blockAt(image.Pt(x, y), image.Pt(width, height))
+	if !ok {
+		a.selection.setTranslation("")
+		return
+	}
+	go a.translateSelection(b)
+}
+
+func (a *App) translateSelection(b block) {
+	ctx, cancel := a.translationContext()
+	defer cancel()
+
+	translation, err := a.translator.Translate(ctx, b.text)
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to translate the selected block")
+		return
+	}
+	log.Info().Msgf("selected block translated: %s", translation)
+	a.selection.setTranslation(translation)
+}
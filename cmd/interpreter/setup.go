@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bquenin/captured"
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"golang.org/x/text/language"
+)
+
+// listWindows prints the title of every window that can be captured.
+func listWindows(w io.Writer) ([]*captured.WindowInfo, error) {
+	windows, err := captured.Captured.ListWindows()
+	if err != nil {
+		return nil, err
+	}
+	for i, window := range windows {
+		fmt.Fprintf(w, "%3d. %s (%dx%d)\n", i+1, window.Title, window.Width, window.Height)
+	}
+	return windows, nil
+}
+
+// setup interactively asks for the essential settings, checks the translator credentials and writes the configuration file.
+func setup(in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	ask := func(question, defaultValue string) (string, error) {
+		fmt.Fprintf(out, "%s [%s]: ", question, defaultValue)
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if answer = strings.TrimSpace(answer); answer == "" {
+			return defaultValue, nil
+		}
+		return answer, nil
+	}
+
+	// Window title
+	fmt.Fprintln(out, "Windows currently open:")
+	windows, err := listWindows(out)
+	if err != nil {
+		return err
+	}
+	windowTitle, err := ask("Window to capture (number from the list or part of the title)", "1")
+	if err != nil {
+		return err
+	}
+	if i, err := strconv.Atoi(windowTitle); err == nil {
+		if i < 1 || i > len(windows) {
+			return fmt.Errorf("no window number %d", i)
+		}
+		windowTitle = windows[i-1].Title
+	}
+
+	// Translator
+	config := &configuration.Configuration{WindowTitle: windowTitle}
+	if config.Translator.API, err = ask(`Translator ("google", "deepl" or "libretranslate")`, "google"); err != nil {
+		return err
+	}
+	switch config.Translator.API {
+	case "google", "deepl", "libretranslate":
+	default:
+		return fmt.Errorf("unsupported translator api: %s", config.Translator.API)
+	}
+	target, err := ask("Target language", "en")
+	if err != nil {
+		return err
+	}
+	if _, err := language.Parse(target); err != nil {
+		return fmt.Errorf("invalid target language %s: %w", target, err)
+	}
+	config.Translator.To = []string{target}
+	switch config.Translator.API {
+	case "deepl":
+		if config.Translator.AuthenticationKey, err = ask("DeepL authentication key", ""); err != nil {
+			return err
+		}
+	case "libretranslate":
+		if config.Translator.Endpoint, err = ask("LibreTranslate endpoint", "http://localhost:5000"); err != nil {
+			return err
+		}
+		if config.Translator.AuthenticationKey, err = ask("LibreTranslate API key, if required", ""); err != nil {
+			return err
+		}
+	}
+
+	// Check credentials
+	fmt.Fprintln(out, "Checking translator credentials...")
+	translator, err := config.GetTranslator()
+	if err != nil {
+		return err
+	}
+	defer translator.Close()
+	if err := checkTranslator(translator); err != nil {
+		return fmt.Errorf("unable to translate with %s, please check your credentials: %w", config.Translator.API, err)
+	}
+
+	if err := configuration.WriteSetup(config); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Configuration file successfully created.")
+	return nil
+}
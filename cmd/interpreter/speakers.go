@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/bquenin/interpreter/internal/frame"
+)
+
+// speakerTolerance is how much the channels of two text colors may differ for them to belong to the same speaker.
+const speakerTolerance = 48
+
+// assignSpeakers sets the speaker of each block according to the color of its text in the screenshot.
+func (a *App) assignSpeakers(screenshot image.Image, blocks []block) {
+	for i := range blocks {
+		if c, ok := frame.TextColor(screenshot, blocks[i].bounds); ok {
+			blocks[i].speaker = a.speakerOf(c)
+		}
+	}
+}
+
+// speakerOf returns the number of the speaker whose text has the given color, numbering new colors as they appear
+// so that a speaker keeps the same number for the whole session.
+func (a *App) speakerOf(c color.RGBA) int {
+	near := func(x, y uint8) bool {
+		d := int(x) - int(y)
+		return d <= speakerTolerance && d >= -speakerTolerance
+	}
+	for i, known := range a.speakers {
+		if near(c.R, known.R) && near(c.G, known.G) && near(c.B, known.B) {
+			return i + 1
+		}
+	}
+	a.speakers = append(a.speakers, c)
+	return len(a.speakers)
+}
+
+// translateBySpeaker translates the consecutive blocks of the same speaker together, one line per speaker turn.
+func (a *App) translateBySpeaker(ctx context.Context, blocks []block) (string, error) {
+	var lines []string
+	for start := 0; start < len(blocks); {
+		end := start + 1
+		for end < len(blocks) && blocks[end].speaker == blocks[start].speaker {
+			end++
+		}
+		translation, err := a.translator.Translate(ctx, joinBlocks(blocks[start:end]))
+		if err != nil {
+			return "", err
+		}
+		if speaker := blocks[start].speaker; speaker > 0 {
+			translation = fmt.Sprintf("Speaker %d: %s", speaker, translation)
+		}
+		lines = append(lines, translation)
+		start = end
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"image/color"
+	"time"
+)
+
+const (
+	staleMarker = "…"
+	staleAlpha  = 0.5
+)
+
+// isStale reports whether the subtitles displayed are older than the stale threshold while newer text is waiting for its translation.
+func (a *App) isStale() bool {
+	return a.staleThreshold > 0 && !a.pendingSince.IsZero() && time.Since(a.displayedAt) > a.staleThreshold
+}
+
+// dim makes a color half transparent. The color being premultiplied, all its components are halved.
+func dim(c color.RGBA) color.RGBA {
+	return color.RGBA{R: c.R / 2, G: c.G / 2, B: c.B / 2, A: c.A / 2}
+}
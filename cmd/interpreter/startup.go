@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// isStarting reports whether the startup delay, letting the game load before the first capture, is still running.
+func (a *App) isStarting() bool {
+	return time.Now().Before(a.startAt)
+}
+
+// drawCountdown draws the seconds left before the first capture in place of the subtitles.
+func (a *App) drawCountdown(screen *ebiten.Image, width int) {
+	message := fmt.Sprintf("Starting in %ds", int(math.Ceil(time.Until(a.startAt).Seconds())))
+	bound := text.BoundString(a.subsFont, message)
+	height := a.subsFont.Metrics().Height.Round()
+	x := (width - bound.Dx()) / 2
+	if x < 0 {
+		x = 0
+	}
+	ebitenutil.DrawRect(screen, float64(x), 0, float64(bound.Dx()), float64(bound.Dy()+height), a.subsBackgroundColor)
+	text.Draw(screen, message, a.subsFont, x, height, a.subsFontColor)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/bquenin/interpreter/internal/translate"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/image/font/basicfont"
+)
+
+const (
+	statusMargin      = 4
+	usagePollInterval = time.Minute
+)
+
+// Stages of the pipeline whose errors are counted
+const (
+	stageCapture   = "capture"
+	stageOCR       = "ocr"
+	stageTranslate = "translate"
+)
+
+// stats holds the metrics of the session.
+type stats struct {
+	mu           sync.Mutex
+	lastLatency  time.Duration
+	characters   int
+	usage        *translate.Usage
+	frames       int
+	cachedFrames int
+	ocrCalls     int
+	translations int
+	errors       map[string]int
+}
+
+func (s *stats) recordFrame(cached bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames++
+	if cached {
+		s.cachedFrames++
+	}
+}
+
+func (s *stats) recordOCR() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ocrCalls++
+}
+
+func (s *stats) recordError(stage string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.errors == nil {
+		s.errors = make(map[string]int)
+	}
+	s.errors[stage]++
+}
+
+func (s *stats) recordTranslation(source string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastLatency = latency
+	s.characters += utf8.RuneCountInString(source)
+	s.translations++
+}
+
+func (s *stats) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := fmt.Sprintf("latency %s | %d chars", s.lastLatency.Round(time.Millisecond), s.characters)
+	if s.usage != nil && s.usage.Limit > 0 {
+		status += fmt.Sprintf(" | quota %d/%d left", s.usage.Limit-s.usage.Used, s.usage.Limit)
+	}
+	return status
+}
+
+func (s *stats) recordUsage(usage *translate.Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = usage
+}
+
+// pollUsage periodically queries the translator quota usage for the status line.
+// The translator is unwrapped on every poll, as the wrapped one may change at runtime.
+func (a *App) pollUsage(translator translate.Translator) {
+	for ; ; time.Sleep(usagePollInterval) {
+		reporter, ok := translate.AsUsageReporter(translator)
+		if !ok {
+			a.stats.recordUsage(nil)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(a.ctx, usagePollInterval)
+		usage, err := reporter.Usage(ctx)
+		cancel()
+		if a.ctx.Err() != nil { // Shutting down
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Msg("unable to get translator usage")
+			continue
+		}
+		log.Debug().Msgf("translator usage: %d/%d characters", usage.Used, usage.Limit)
+		a.stats.recordUsage(usage)
+	}
+}
+
+// drawStatusLine draws the provider name and translation metrics in the configured corner of the window.
+func (a *App) drawStatusLine(screen *ebiten.Image, width, height int) {
+	face := basicfont.Face7x13
+	status := fmt.Sprintf("%s | %s", a.translatorName, a.stats.String())
+	bound := text.BoundString(face, status)
+
+	x, y := statusMargin, statusMargin+face.Ascent
+	switch a.statusCorner {
+	case configuration.CornerTopRight:
+		x = width - bound.Dx() - statusMargin
+	case configuration.CornerBottomLeft:
+		y = height - face.Descent - statusMargin
+	case configuration.CornerBottomRight:
+		x, y = width-bound.Dx()-statusMargin, height-face.Descent-statusMargin
+	}
+	text.Draw(screen, status, face, x, y, a.subsFontColor)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/bquenin/interpreter/internal/cleanup"
+	"github.com/rs/zerolog/log"
+)
+
+// readLines sends the lines read from r to the returned channel, which is closed at the end of the input.
+func readLines(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		if err := scanner.Err(); err != nil {
+			log.Warn().Err(err).Msg("unable to read the standard input")
+		}
+	}()
+	return lines
+}
+
+// refreshFromStdin translates the next line read from the standard input instead of the text of a captured frame.
+// The subtitles are left unchanged when no line is available yet or at the end of the input.
+func (a *App) refreshFromStdin() error {
+	var text string
+	select {
+	case line, ok := <-a.stdinLines:
+		if !ok {
+			return nil
+		}
+		text = cleanup.Normalize(strings.TrimSpace(line), a.normalize)
+	default:
+		return nil
+	}
+
+	subs, changed, err := a.processText(text, []block{{text: text}}, false)
+	if err != nil {
+		return err
+	}
+	a.show(subs, changed)
+	return nil
+}
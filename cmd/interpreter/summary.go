@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// List prices in US dollars, before any free tier
+const (
+	visionCostPerCall      = 1.5 / 1000
+	googleCostPerCharacter = 20.0 / 1_000_000
+	deeplCostPerCharacter  = 25.0 / 1_000_000
+)
+
+// cost estimates the price of the session API calls with the given translator.
+func (s *stats) cost(translator string) float64 {
+	cost := float64(s.ocrCalls) * visionCostPerCall
+	switch translator {
+	case "google":
+		cost += float64(s.characters) * googleCostPerCharacter
+	case "deepl":
+		cost += float64(s.characters) * deeplCostPerCharacter
+	}
+	return cost
+}
+
+// writeSummary writes a table of the session metrics, printed on exit.
+func (s *stats) writeSummary(w io.Writer, translator string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var hitRate float64
+	if s.frames > 0 {
+		hitRate = float64(s.cachedFrames) / float64(s.frames) * 100
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Session summary")
+	fmt.Fprintf(table, "frames captured\t%d\n", s.frames)
+	fmt.Fprintf(table, "frame cache hit rate\t%.1f%%\n", hitRate)
+	fmt.Fprintf(table, "OCR calls\t%d\n", s.ocrCalls)
+	fmt.Fprintf(table, "translate calls\t%d\n", s.translations)
+	fmt.Fprintf(table, "characters translated\t%d\n", s.characters)
+	fmt.Fprintf(table, "estimated cost\t$%.4f\n", s.cost(translator))
+
+	stages := make([]string, 0, len(s.errors))
+	for stage := range s.errors {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		fmt.Fprintf(table, "%s errors\t%d\n", stage, s.errors[stage])
+	}
+	_ = table.Flush()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// ticker scrolls the translations right to left along the bottom of the window. New translations are queued behind
+// the one scrolling.
+type ticker struct {
+	face            font.Face
+	speed           float64 // In pixels per second
+	fontColor       color.RGBA
+	backgroundColor color.RGBA
+	source          string // Subtitles last queued
+	queue           []string
+	current         string
+	offset          float64
+	lastDraw        time.Time
+}
+
+func (t *ticker) draw(screen *ebiten.Image, subs string, width, height int) {
+	now := time.Now()
+	elapsed := now.Sub(t.lastDraw)
+	t.lastDraw = now
+
+	if subs != t.source {
+		t.source = subs
+		if line := strings.Join(strings.Fields(subs), " "); line != "" {
+			t.queue = append(t.queue, line)
+		}
+	}
+	if t.current == "" {
+		if len(t.queue) == 0 {
+			return
+		}
+		t.current, t.queue, t.offset, elapsed = t.queue[0], t.queue[1:], 0, 0
+	}
+	t.offset += t.speed * elapsed.Seconds()
+
+	lineHeight := t.face.Metrics().Height.Round()
+	barHeight := lineHeight * 3 / 2
+	x := width - int(t.offset)
+	if x+text.BoundString(t.face, t.current).Dx() < 0 { // Scrolled out, the next one comes in at the next frame
+		t.current = ""
+		return
+	}
+	ebitenutil.DrawRect(screen, 0, float64(height-barHeight), float64(width), float64(barHeight), t.backgroundColor)
+	text.Draw(screen, t.current, t.face, x, height-barHeight+lineHeight, t.fontColor)
+}
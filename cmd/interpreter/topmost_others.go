@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+// bringToTop places the overlay window above the other windows. It's only supported on Windows.
+func bringToTop(title string) bool {
+	return false
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	hwndTopmost  = ^uintptr(0) // HWND_TOPMOST (-1)
+	swpNoSize    = 0x0001
+	swpNoMove    = 0x0002
+	swpNoActive  = 0x0010
+	topmostFlags = swpNoSize | swpNoMove | swpNoActive
+)
+
+var (
+	findWindow   = windows.NewLazySystemDLL("user32.dll").NewProc("FindWindowW")
+	setWindowPos = windows.NewLazySystemDLL("user32.dll").NewProc("SetWindowPos")
+)
+
+// bringToTop places the overlay window above all the non topmost windows without activating it.
+func bringToTop(title string) bool {
+	titlePtr, err := windows.UTF16PtrFromString(title)
+	if err != nil {
+		return false
+	}
+	hwnd, _, _ := findWindow.Call(0, uintptr(unsafe.Pointer(titlePtr)))
+	if hwnd == 0 {
+		return false
+	}
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(windows.HWND(hwnd), &pid); err != nil || int(pid) != os.Getpid() {
+		return false
+	}
+	ok, _, _ := setWindowPos.Call(hwnd, hwndTopmost, 0, 0, 0, 0, topmostFlags)
+	return ok != 0
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type transcriptEntry struct {
+	Time        time.Time `json:"time"`
+	Source      string    `json:"source"`
+	Translation string    `json:"translation"`
+}
+
+// transcript appends the subtitles to a JSON Lines file as they're displayed, unlike export-on-exit which only writes
+// them on exit.
+type transcript struct {
+	mu      sync.Mutex
+	writer  *bufio.Writer
+	closer  io.Closer
+	encoder *json.Encoder
+	closed  bool // The entries of the refreshes ending after the game are dropped
+}
+
+func newTranscript(w io.WriteCloser) *transcript {
+	writer := bufio.NewWriter(w)
+	return &transcript{writer: writer, closer: w, encoder: json.NewEncoder(writer)}
+}
+
+// openTranscript opens the transcript file, the entries of the previous sessions being kept.
+func openTranscript(name string) (*transcript, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return newTranscript(f), nil
+}
+
+// add writes an entry, flushed right away not to be lost if the session ends abruptly.
+func (t *transcript) add(source, translation string, at time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	if err := t.encoder.Encode(transcriptEntry{Time: at, Source: source, Translation: translation}); err != nil {
+		return err
+	}
+	return t.writer.Flush()
+}
+
+func (t *transcript) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	if err := t.writer.Flush(); err != nil {
+		t.closer.Close()
+		return err
+	}
+	return t.closer.Close()
+}
+
+// updateTranscript adds the subtitles displayed to the transcript, if any.
+func (a *App) updateTranscript(source, translation string, at time.Time) {
+	if a.transcript == nil || translation == "" {
+		return
+	}
+	if err := a.transcript.add(source, translation, at); err != nil {
+		log.Warn().Err(err).Msg("unable to update the transcript")
+	}
+}
+
+// closeTranscript flushes and closes the transcript, on exit.
+func (a *App) closeTranscript() {
+	if a.transcript == nil {
+		return
+	}
+	if err := a.transcript.Close(); err != nil {
+		log.Warn().Err(err).Msg("unable to close the transcript")
+	}
+}
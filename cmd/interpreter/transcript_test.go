@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryFile is an in-memory transcript file, counting the writes reaching it.
+type memoryFile struct {
+	bytes.Buffer
+	writes int
+	closed bool
+}
+
+func (f *memoryFile) Write(p []byte) (int, error) {
+	f.writes++
+	return f.Buffer.Write(p)
+}
+
+func (f *memoryFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+// entries decodes the JSON lines of the transcript.
+func (f *memoryFile) entries(t *testing.T) []transcriptEntry {
+	t.Helper()
+	var entries []transcriptEntry
+	for _, line := range strings.Split(strings.TrimSuffix(f.String(), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry transcriptEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid transcript line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestTranscript(t *testing.T) {
+	f := &memoryFile{}
+	tr := newTranscript(f)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Each entry is flushed to the file right away
+	want := []transcriptEntry{
+		{Time: at, Source: "こんにちは", Translation: "Hello"},
+		{Time: at.Add(time.Second), Source: "さようなら", Translation: "Goodbye"},
+	}
+	for i, entry := range want {
+		if err := tr.add(entry.Source, entry.Translation, entry.Time); err != nil {
+			t.Fatal(err)
+		}
+		if f.writes != i+1 {
+			t.Errorf("%d writes after %d entries, want an entry flushed at a time", f.writes, i+1)
+		}
+		if entries := f.entries(t); !reflect.DeepEqual(entries, want[:i+1]) {
+			t.Errorf("transcript = %v, want %v", entries, want[:i+1])
+		}
+	}
+
+	// The entries of the refreshes ending after the game are dropped
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !f.closed {
+		t.Error("transcript file not closed")
+	}
+	if err := tr.add("遅い", "Late", at.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if entries := f.entries(t); !reflect.DeepEqual(entries, want) {
+		t.Errorf("transcript = %v after closing, want %v", entries, want)
+	}
+}
+
+func TestUpdateTranscript(t *testing.T) {
+	f := &memoryFile{}
+	a := &App{transcript: newTranscript(f)}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// The subtitles cleared aren't written
+	a.updateTranscript("こんにちは", "Hello", at)
+	a.updateTranscript("", "", at.Add(time.Second))
+	a.closeTranscript()
+
+	if entries, want := f.entries(t), []transcriptEntry{{Time: at, Source: "こんにちは", Translation: "Hello"}}; !reflect.DeepEqual(entries, want) {
+		t.Errorf("transcript = %v, want %v", entries, want)
+	}
+
+	// Without a transcript, nothing happens
+	(&App{}).updateTranscript("こんにちは", "Hello", at)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bquenin/interpreter/cmd/interpreter/configuration"
+	"github.com/bquenin/interpreter/internal/capture"
+	"github.com/bquenin/interpreter/internal/frame"
+	"github.com/rs/zerolog/log"
+)
+
+// translateVideo runs the pipeline on the frames of a video sampled at the refresh rate, and writes the subtitles
+// to an SRT file next to the video. It returns the name of the SRT file.
+func (a *App) translateVideo(path string) (string, error) {
+	video, err := capture.NewVideo(path, a.refreshRate)
+	if err != nil {
+		return "", err
+	}
+	defer video.Close()
+
+	var offset time.Duration
+	for i := 1; ; i++ {
+		img, err := video.Capture()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		subs, changed, err := a.process(frame.RGBA(img))
+		if err != nil {
+			log.Warn().Err(err).Msgf("unable to process frame %d at %s, skipping it", i, offset)
+		} else if changed {
+			source := a.getLastText()
+			a.setSubs(subs, source)
+			a.history.add(source, subs, a.history.start.Add(offset))
+		}
+		log.Info().Msgf("processed frame %d at %s", i, offset)
+		offset += a.refreshRate
+	}
+
+	name := strings.TrimSuffix(filepath.Clean(path), filepath.Ext(path)) + ".srt"
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	if err := a.history.export(f, configuration.ExportSRT, a.history.start.Add(offset)); err != nil {
+		f.Close()
+		return "", err
+	}
+	return name, f.Close()
+}
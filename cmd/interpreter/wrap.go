@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// noLineStart holds the characters a line can't start with, such as CJK closing punctuation or small kana.
+const noLineStart = ",.!?:;)]}、。，．：；！？）］｝」』】〕〉》・ー…々ぁぃぅぇぉっゃゅょゎァィゥェォッャュョヮヵヶ"
+
+// wrapText splits the text into lines fitting the given width, between words when they are separated by spaces.
+// Words wider than the width, such as Japanese or Chinese sentences, are broken between characters.
+func wrapText(face font.Face, s string, maxWidth int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		lines = append(lines, wrapParagraph(face, paragraph, maxWidth)...)
+	}
+	return lines
+}
+
+func wrapParagraph(face font.Face, paragraph string, maxWidth int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(paragraph) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if text.BoundString(face, candidate).Dx() <= maxWidth {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+			line = ""
+		}
+		for _, r := range word {
+			if line != "" && !strings.ContainsRune(noLineStart, r) && text.BoundString(face, line+string(r)).Dx() > maxWidth {
+				lines = append(lines, line)
+				line = ""
+			}
+			line += string(r)
+		}
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// fixedFace is a font face whose glyphs are all the same size, for widths to be measured in runes.
+type fixedFace struct {
+	advance int
+}
+
+func (f fixedFace) Close() error { return nil }
+
+func (f fixedFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return image.Rectangle{}, nil, image.Point{}, fixed.I(f.advance), true
+}
+
+func (f fixedFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return fixed.R(0, -f.advance, f.advance, 0), fixed.I(f.advance), true
+}
+
+func (f fixedFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return fixed.I(f.advance), true
+}
+
+func (f fixedFace) Kern(r0, r1 rune) fixed.Int26_6 { return 0 }
+
+func (f fixedFace) Metrics() font.Metrics {
+	return font.Metrics{Height: fixed.I(f.advance), Ascent: fixed.I(f.advance)}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{"empty", "", []string{""}},
+		{"fits", "ab cd", []string{"ab cd"}},
+		{"words", "the quick brown fox", []string{"the", "quick", "brown", "fox"}},
+		{"spaces", "ab   cd", []string{"ab cd"}},
+		{"paragraphs", "ab\ncd", []string{"ab", "cd"}},
+		{"long word", "abcdefghijkl", []string{"abcde", "fghij", "kl"}},
+		{"japanese", "こんにちは世界です", []string{"こんにちは", "世界です"}},
+		{"mixed", "Hello 世界の皆さん", []string{"Hello", "世界の皆さ", "ん"}},
+		{"mixed on one line", "Hi 世界", []string{"Hi 世界"}},
+		{"closing punctuation", "こんにちは。元気", []string{"こんにちは。", "元気"}},
+		{"closing bracket", "こんにちは」です", []string{"こんにちは」", "です"}},
+		{"small kana", "あいうえおっと", []string{"あいうえおっ", "と"}},
+		{"long vowel mark", "あいうえおーい", []string{"あいうえおー", "い"}},
+		{"latin punctuation", "abcde, fg", []string{"abcde,", "fg"}},
+	}
+	face := fixedFace{advance: 10}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if lines := wrapText(face, test.s, 50); !reflect.DeepEqual(lines, test.want) {
+				t.Errorf("wrapText(%q) = %q, want %q", test.s, lines, test.want)
+			}
+		})
+	}
+}
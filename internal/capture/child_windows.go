@@ -0,0 +1,107 @@
+package capture
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+	"golang.org/x/sys/windows"
+)
+
+var (
+	getWindowTextLength = windows.NewLazySystemDLL("user32.dll").NewProc("GetWindowTextLengthW")
+	getWindowText       = windows.NewLazySystemDLL("user32.dll").NewProc("GetWindowTextW")
+)
+
+// The enumeration callbacks are created once, as Windows only allows a limited number of them.
+// They report to the search below, hence the lock.
+var (
+	searchLock sync.Mutex
+	search     struct {
+		title, class string
+		found        win.HWND
+		area         int
+	}
+	topLevelCallback = syscall.NewCallback(func(hWnd win.HWND, _ uintptr) uintptr {
+		// The windows of this process, such as the overlay, are never captured
+		if !win.IsWindowVisible(hWnd) || !(candidate{windowText(hWnd), ownWindow(hWnd)}).matches(search.title, "") {
+			return 1 // continue enumeration
+		}
+		search.found = hWnd
+		return 0
+	})
+	childCallback = syscall.NewCallback(func(hWnd win.HWND, _ uintptr) uintptr {
+		if !win.IsWindowVisible(hWnd) {
+			return 1
+		}
+		if search.class != "" {
+			buffer := make([]uint16, 256)
+			if _, err := win.GetClassName(hWnd, &buffer[0], len(buffer)); err != nil || !strings.EqualFold(windows.UTF16ToString(buffer), search.class) {
+				return 1
+			}
+		}
+		var rect win.RECT
+		win.GetClientRect(hWnd, &rect)
+		if area := int(rect.Right-rect.Left) * int(rect.Bottom-rect.Top); area > search.area {
+			search.found, search.area = hWnd, area
+		}
+		return 1
+	})
+)
+
+// ChildWindow captures a child window of the first window whose title contains the given title.
+// Emulators and some games render into a child window that capturing the top level window misses.
+type ChildWindow struct {
+	title, class string
+}
+
+// NewChildWindow returns a capturer of the child window having the given class, or of the largest child window when class is empty.
+func NewChildWindow(title, class string) (Capturer, error) {
+	return &ChildWindow{title, class}, nil
+}
+
+func (c *ChildWindow) Capture() (image.Image, error) {
+	child, err := c.find()
+	if err != nil {
+		return nil, err
+	}
+	var rect win.RECT
+	if !win.GetClientRect(child, &rect) {
+		return nil, fmt.Errorf("GetClientRect failed")
+	}
+	return captureDC(child, image.Rect(0, 0, int(rect.Right), int(rect.Bottom)))
+}
+
+func (c *ChildWindow) find() (win.HWND, error) {
+	searchLock.Lock()
+	defer searchLock.Unlock()
+
+	search.title, search.class, search.found, search.area = strings.ToLower(c.title), "", 0, 0
+	// EnumWindows reports an error when the enumeration is stopped, the result is in search.found instead
+	windows.EnumWindows(topLevelCallback, nil)
+	if search.found == 0 {
+		return 0, fmt.Errorf(`no window title containing "%s" found`, c.title)
+	}
+	parent := search.found
+
+	search.class, search.found = c.class, 0
+	win.EnumChildWindows(parent, childCallback, 0)
+	if search.found == 0 {
+		if c.class != "" {
+			return 0, fmt.Errorf(`no child window of class "%s" found in "%s"`, c.class, c.title)
+		}
+		return 0, fmt.Errorf(`no child window found in "%s"`, c.title)
+	}
+	return search.found, nil
+}
+
+func windowText(hWnd win.HWND) string {
+	length, _, _ := getWindowTextLength.Call(uintptr(hWnd))
+	buffer := make([]uint16, length+1)
+	getWindowText.Call(uintptr(hWnd), uintptr(unsafe.Pointer(&buffer[0])), length+1)
+	return windows.UTF16ToString(buffer)
+}
@@ -0,0 +1,42 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/lxn/win"
+	"golang.org/x/sys/windows"
+)
+
+// Foreground captures the client area of the focused window, whichever it is. When this process is focused, such as
+// when the overlay is clicked, the window focused before it is captured instead.
+type Foreground struct {
+	last win.HWND
+}
+
+func NewForeground() (Capturer, error) {
+	return &Foreground{}, nil
+}
+
+func (f *Foreground) Capture() (image.Image, error) {
+	if hWnd := win.GetForegroundWindow(); hWnd != 0 && !ownWindow(hWnd) {
+		f.last = hWnd
+	}
+	if f.last == 0 || !windows.IsWindow(windows.HWND(f.last)) {
+		return nil, errors.New("no foreground window to capture, focus the window to translate")
+	}
+	var rect win.RECT
+	if !win.GetClientRect(f.last, &rect) {
+		return nil, fmt.Errorf("GetClientRect failed")
+	}
+	return captureDC(f.last, image.Rect(0, 0, int(rect.Right), int(rect.Bottom)))
+}
+
+// ownWindow reports whether the window belongs to this process.
+func ownWindow(hWnd win.HWND) bool {
+	var pid uint32
+	_, err := windows.GetWindowThreadProcessId(windows.HWND(hWnd), &pid)
+	return err == nil && int(pid) == os.Getpid()
+}
@@ -0,0 +1,72 @@
+package capture
+
+import (
+	"errors"
+	"image"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// captureDC copies the given rectangle of the device context of a window, or of the whole screen when hWnd is 0.
+func captureDC(hWnd win.HWND, rect image.Rectangle) (*image.RGBA, error) {
+	width, height := rect.Dx(), rect.Dy()
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("nothing to capture, the window is minimized or empty")
+	}
+
+	hdc := win.GetDC(hWnd)
+	if hdc == 0 {
+		return nil, errors.New("GetDC failed")
+	}
+	defer win.ReleaseDC(hWnd, hdc)
+
+	memoryDevice := win.CreateCompatibleDC(hdc)
+	if memoryDevice == 0 {
+		return nil, errors.New("CreateCompatibleDC failed")
+	}
+	defer win.DeleteDC(memoryDevice)
+
+	bitmap := win.CreateCompatibleBitmap(hdc, int32(width), int32(height))
+	if bitmap == 0 {
+		return nil, errors.New("CreateCompatibleBitmap failed")
+	}
+	defer win.DeleteObject(win.HGDIOBJ(bitmap))
+
+	var header win.BITMAPINFOHEADER
+	header.BiSize = uint32(unsafe.Sizeof(header))
+	header.BiPlanes = 1
+	header.BiBitCount = 32
+	header.BiWidth = int32(width)
+	header.BiHeight = int32(-height)
+	header.BiCompression = win.BI_RGB
+
+	// GetDIBits balks at using Go memory on some systems, see
+	// https://docs.microsoft.com/en-gb/windows/desktop/gdi/capturing-an-image
+	size := uintptr(width * height * 4)
+	hmem := win.GlobalAlloc(win.GMEM_MOVEABLE, size)
+	defer win.GlobalFree(hmem)
+	memptr := win.GlobalLock(hmem)
+	defer win.GlobalUnlock(hmem)
+
+	old := win.SelectObject(memoryDevice, win.HGDIOBJ(bitmap))
+	if old == 0 {
+		return nil, errors.New("SelectObject failed")
+	}
+	defer win.SelectObject(memoryDevice, old)
+
+	if !win.BitBlt(memoryDevice, 0, 0, int32(width), int32(height), hdc, int32(rect.Min.X), int32(rect.Min.Y), win.SRCCOPY) {
+		return nil, errors.New("BitBlt failed")
+	}
+	if win.GetDIBits(hdc, bitmap, 0, uint32(height), (*uint8)(memptr), (*win.BITMAPINFO)(unsafe.Pointer(&header)), win.DIB_RGB_COLORS) == 0 {
+		return nil, errors.New("GetDIBits failed")
+	}
+
+	// BGRA => RGBA, and set A to 255
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	pixels := unsafe.Slice((*uint8)(memptr), size)
+	for i := 0; i < len(pixels); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = pixels[i+2], pixels[i+1], pixels[i], 255
+	}
+	return img, nil
+}
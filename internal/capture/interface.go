@@ -0,0 +1,8 @@
+package capture
+
+import "image"
+
+// Capturer provides the images the text is extracted from.
+type Capturer interface {
+	Capture() (image.Image, error)
+}
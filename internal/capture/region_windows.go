@@ -0,0 +1,16 @@
+package capture
+
+import "image"
+
+// ScreenRegion captures a zone of the screen, whatever the windows displayed there.
+type ScreenRegion struct {
+	rect image.Rectangle
+}
+
+func NewScreenRegion(rect image.Rectangle) (Capturer, error) {
+	return &ScreenRegion{rect}, nil
+}
+
+func (r *ScreenRegion) Capture() (image.Image, error) {
+	return captureDC(0, r.rect)
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package capture
+
+import (
+	"errors"
+	"image"
+)
+
+func NewScreenRegion(rect image.Rectangle) (Capturer, error) {
+	return nil, errors.New("the screen-region capture backend is only available on Windows")
+}
+
+func NewChildWindow(title, class string) (Capturer, error) {
+	return nil, errors.New("the child-window capture backend is only available on Windows")
+}
+
+func NewForeground() (Capturer, error) {
+	return nil, errors.New("capturing the foreground window is only available on Windows")
+}
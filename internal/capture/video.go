@@ -0,0 +1,122 @@
+package capture
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Video captures the frames of a video file sampled at a fixed interval, decoded by the ffmpeg binary,
+// or the images of a directory in name order. Capture returns io.EOF after the last frame.
+type Video struct {
+	frames func() (image.Image, error)
+	close  func() error
+}
+
+func NewVideo(path string, interval time.Duration) (*Video, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return newImageSequence(path)
+	}
+	return newFFmpeg(path, interval)
+}
+
+func (v *Video) Capture() (image.Image, error) {
+	return v.frames()
+}
+
+func (v *Video) Close() error {
+	return v.close()
+}
+
+// newFFmpeg streams the sampled frames as PNG images from ffmpeg standard output.
+func newFFmpeg(path string, interval time.Duration) (*Video, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, errors.New("ffmpeg is required to decode video files, install it or extract the frames to a directory")
+	}
+	cmd := exec.Command(ffmpeg, "-loglevel", "error", "-i", path,
+		"-vf", fmt.Sprintf("fps=1/%f", interval.Seconds()), "-f", "image2pipe", "-vcodec", "png", "-")
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(stdout)
+	exited := false
+	frames := func() (image.Image, error) {
+		if _, err := reader.Peek(1); err != nil {
+			// The output ends when ffmpeg exits, which may be a failure to decode the video rather than its end
+			if !exited {
+				exited = true
+				if err := cmd.Wait(); err != nil {
+					return nil, fmt.Errorf("unable to decode %s with ffmpeg: %w", path, err)
+				}
+			}
+			return nil, io.EOF
+		}
+		return png.Decode(reader)
+	}
+	closer := func() error {
+		if exited {
+			return nil
+		}
+		exited = true
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil
+	}
+	return &Video{frames, closer}, nil
+}
+
+// newImageSequence reads the PNG and JPEG images of a directory, one per frame.
+func newImageSequence(dir string) (*Video, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".png", ".jpg", ".jpeg":
+			names = append(names, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(names)
+
+	frames := func() (image.Image, error) {
+		if len(names) == 0 {
+			return nil, io.EOF
+		}
+		name := names[0]
+		names = names[1:]
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode image %s: %w", name, err)
+		}
+		return img, nil
+	}
+	return &Video{frames, func() error { return nil }}, nil
+}
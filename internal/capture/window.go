@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"unicode"
+
+	"github.com/bquenin/captured"
+)
+
+// Window captures the first window whose title contains the given title, without its title bar.
+// The window titled ownTitle is never captured, as the title searched may be part of it: capturing the overlay would
+// translate its own subtitles.
+type Window struct {
+	title    string
+	ownTitle string
+}
+
+func NewWindow(title, ownTitle string) *Window {
+	return &Window{title, ownTitle}
+}
+
+func (w *Window) Capture() (image.Image, error) {
+	windows, err := captured.Captured.ListWindows()
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]candidate, 0, len(windows))
+	for _, window := range windows {
+		candidates = append(candidates, candidate{title: window.Title})
+	}
+	if i := selectWindow(candidates, w.title, w.ownTitle); i >= 0 {
+		return captured.Captured.CaptureWindow(windows[i], captured.CropTitle)
+	}
+	return nil, fmt.Errorf(`no window title containing "%s" found`, w.title)
+}
+
+// candidate is a window that may be captured.
+type candidate struct {
+	title string
+	own   bool // Belongs to this process, such as the overlay
+}
+
+// matches reports whether the window title contains the title searched, the windows of this process and the one
+// titled ownTitle never matching.
+func (c candidate) matches(title, ownTitle string) bool {
+	windowTitle := printable(c.title)
+	return !c.own && windowTitle != ownTitle && strings.Contains(strings.ToLower(windowTitle), strings.ToLower(title))
+}
+
+// selectWindow returns the index of the first window matching the title searched, -1 if none does.
+func selectWindow(candidates []candidate, title, ownTitle string) int {
+	for i, c := range candidates {
+		if c.matches(title, ownTitle) {
+			return i
+		}
+	}
+	return -1
+}
+
+// printable removes the characters some windows have in their title that can't be typed, such as zero-width spaces.
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,30 @@
+package capture
+
+import "testing"
+
+func TestSelectWindow(t *testing.T) {
+	const ownTitle = "Interpreter"
+	tests := []struct {
+		name       string
+		candidates []candidate
+		title      string
+		want       int
+	}{
+		{"match", []candidate{{title: "Notepad"}, {title: "Tales of Phantasia"}}, "tales", 1},
+		{"first match", []candidate{{title: "Tales of Phantasia"}, {title: "Tales of Destiny"}}, "Tales", 0},
+		{"none", []candidate{{title: "Notepad"}}, "Tales", -1},
+		{"own title skipped", []candidate{{title: ownTitle}, {title: "Interpreter demo"}}, "interpreter", 1},
+		{"own title only", []candidate{{title: ownTitle}}, "terp", -1},
+		{"own process skipped", []candidate{{title: "Tales - Interpreter debug", own: true}, {title: "Tales"}}, "Tales", 1},
+		{"own process only", []candidate{{title: "Tales", own: true}}, "Tales", -1},
+		{"non-printable characters", []candidate{{title: "Tales\u200b of Phantasia"}}, "Tales of", 0},
+		{"own title with non-printable characters", []candidate{{title: "Inter\u200bpreter"}}, "Interpreter", -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if i := selectWindow(test.candidates, test.title, ownTitle); i != test.want {
+				t.Errorf("selectWindow(%q) = %d, want %d", test.title, i, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package cleanup
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IsCJK reports whether the rune belongs to a script written without spaces between words.
+func IsCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || // CJK symbols and punctuation
+		(r >= 0xFF00 && r <= 0xFFEF) // Half-width and full-width forms
+}
+
+// StripCJKSpaces removes the spaces found between two CJK characters, which OCR sometimes inserts between kana.
+// Line breaks and spaces next to non CJK characters are kept.
+func StripCJKSpaces(s string) string {
+	runes := []rune(s)
+	var builder strings.Builder
+	builder.Grow(len(s))
+	for i := 0; i < len(runes); i++ {
+		if !isHorizontalSpace(runes[i]) || i == 0 || !IsCJK(runes[i-1]) {
+			builder.WriteRune(runes[i])
+			continue
+		}
+
+		// Look past the run of spaces
+		j := i
+		for j < len(runes) && isHorizontalSpace(runes[j]) {
+			j++
+		}
+		if j < len(runes) && IsCJK(runes[j]) {
+			i = j - 1
+			continue
+		}
+		builder.WriteString(string(runes[i:j]))
+		i = j - 1
+	}
+	return builder.String()
+}
+
+func isHorizontalSpace(r rune) bool {
+	return unicode.IsSpace(r) && r != '\n' && r != '\r'
+}
+
+// Scripts returned by DominantScript
+const (
+	ScriptLatin    = "latin"
+	ScriptJapanese = "ja"
+	ScriptKorean   = "ko"
+	ScriptChinese  = "zh"
+)
+
+// DominantScript classifies the text by the script most of its letters are written in.
+// Han characters alone are considered Chinese, along with kana they are considered Japanese.
+// It returns an empty string if the text has no letters.
+func DominantScript(s string) string {
+	var latin, kana, hangul, han int
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.IsLetter(r):
+			latin++
+		}
+	}
+
+	switch cjk := kana + hangul + han; {
+	case latin == 0 && cjk == 0:
+		return ""
+	case latin > cjk:
+		return ScriptLatin
+	case hangul >= kana+han:
+		return ScriptKorean
+	case kana > 0:
+		return ScriptJapanese
+	default:
+		return ScriptChinese
+	}
+}
@@ -0,0 +1,16 @@
+package cleanup
+
+import "unicode/utf8"
+
+// CommonPrefix returns the longest common prefix of a and b, cut on a rune boundary.
+func CommonPrefix(a, b string) string {
+	n := min(len(a), len(b))
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	for i > 0 && i < len(a) && !utf8.RuneStart(a[i]) {
+		i--
+	}
+	return a[:i]
+}
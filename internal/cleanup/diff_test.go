@@ -0,0 +1,27 @@
+package cleanup
+
+import "testing"
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		name, a, b, want string
+	}{
+		{"identical", "Hello there", "Hello there", "Hello there"},
+		{"appended", "Hello", "Hello there", "Hello"},
+		{"appended to the other", "Hello there", "Hello", "Hello"},
+		{"edited", "Hello there", "Hello world", "Hello "},
+		{"different", "Hello", "World", ""},
+		{"empty", "", "", ""},
+		{"one empty", "", "Hello", ""},
+		{"multibyte appended", "こんにちは", "こんにちは、世界", "こんにちは"},
+		{"multibyte edited", "こんにちは", "こんばんは", "こん"},
+		{"same first byte", "あ", "い", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CommonPrefix(test.a, test.b); got != test.want {
+				t.Errorf("CommonPrefix(%q, %q) = %q, want %q", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
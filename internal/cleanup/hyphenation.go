@@ -0,0 +1,32 @@
+package cleanup
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lineEndHyphen matches a word followed by a hyphen at the end of a line, and the first character of the next line.
+var lineEndHyphen = regexp.MustCompile(`(\p{L}+)-[ \t]*\n[ \t]*(\S)`)
+
+// compoundPrefixes start hyphenated compounds, such as "self-aware", whose hyphen is kept.
+var compoundPrefixes = map[string]bool{
+	"all": true, "anti": true, "co": true, "cross": true, "ex": true, "half": true, "high": true, "low": true,
+	"multi": true, "non": true, "off": true, "old": true, "one": true, "post": true, "pre": true, "pro": true,
+	"self": true, "semi": true, "two": true, "well": true,
+}
+
+// Dehyphenate joins the lines ending with a hyphenated word, such as "magnifi-\ncent", removing the hyphen.
+// The hyphen is kept when the next line doesn't start with a lowercase letter and for compounds starting with a
+// common prefix, such as "self-\naware".
+func Dehyphenate(s string) string {
+	return lineEndHyphen.ReplaceAllStringFunc(s, func(match string) string {
+		parts := lineEndHyphen.FindStringSubmatch(match)
+		next, _ := utf8.DecodeRuneInString(parts[2])
+		if !unicode.IsLower(next) || compoundPrefixes[strings.ToLower(parts[1])] {
+			return parts[1] + "-" + parts[2]
+		}
+		return parts[1] + parts[2]
+	})
+}
@@ -0,0 +1,29 @@
+package cleanup
+
+import "testing"
+
+func TestDehyphenate(t *testing.T) {
+	tests := []struct {
+		name, s, want string
+	}{
+		{"split word", "a magnifi-\ncent view", "a magnificent view"},
+		{"split word with spaces", "magnifi- \n  cent", "magnificent"},
+		{"compound", "a well-\nknown hero", "a well-known hero"},
+		{"compound on one line", "a well-known hero", "a well-known hero"},
+		{"capitalized compound prefix", "Self-\naware", "Self-aware"},
+		{"next line capitalized", "Jean-\nPierre", "Jean-Pierre"},
+		{"next line not a letter", "2-\n3", "2-\n3"},
+		{"dash ending a sentence", "wait -\nthere", "wait -\nthere"},
+		{"several lines", "extra-\nordinary and fan-\ntastic", "extraordinary and fantastic"},
+		{"accented", "déjà-\nvu", "déjàvu"},
+		{"no hyphen", "magnificent\nview", "magnificent\nview"},
+		{"empty", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Dehyphenate(test.s); got != test.want {
+				t.Errorf("Dehyphenate(%q) = %q, want %q", test.s, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package cleanup
+
+import "golang.org/x/text/unicode/norm"
+
+// Unicode normalization forms
+const (
+	NormalizeNone = "none"
+	NormalizeNFC  = "NFC"
+	NormalizeNFKC = "NFKC"
+)
+
+// Normalize applies the Unicode normalization form to s. NFC composes combining characters,
+// NFKC also turns compatibility characters such as full-width forms into their usual equivalent.
+func Normalize(s, form string) string {
+	switch form {
+	case NormalizeNFC:
+		return norm.NFC.String(s)
+	case NormalizeNFKC:
+		return norm.NFKC.String(s)
+	default:
+		return s
+	}
+}
@@ -0,0 +1,28 @@
+package cleanup
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MergeOverlap appends next to previous, skipping the beginning of next that previous already ends with.
+func MergeOverlap(previous, next string) string {
+	for k := min(len(previous), len(next)); k > 0; k-- {
+		if k < len(next) && !utf8.RuneStart(next[k]) {
+			continue
+		}
+		if strings.HasSuffix(previous, next[:k]) {
+			return previous + next[k:]
+		}
+	}
+	return previous + next
+}
+
+// TrimStart keeps at most the last maxLength runes of s.
+func TrimStart(s string, maxLength int) string {
+	runes := []rune(s)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return s
+	}
+	return string(runes[len(runes)-maxLength:])
+}
@@ -0,0 +1,41 @@
+package cleanup
+
+import "strings"
+
+const (
+	sentenceTerminators = "。！？.!?…"
+	closingPunctuation  = "」』）)\"'”’"
+)
+
+// SplitSentences splits s after each sentence terminator. Joining the sentences gives back s.
+func SplitSentences(s string) []string {
+	var sentences []string
+	runes := []rune(s)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(sentenceTerminators, runes[i]) {
+			continue
+		}
+		// Keep consecutive terminators and closing quotes with the sentence
+		for i+1 < len(runes) && strings.ContainsRune(sentenceTerminators+closingPunctuation, runes[i+1]) {
+			i++
+		}
+		sentences = append(sentences, string(runes[start:i+1]))
+		start = i + 1
+	}
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+	return sentences
+}
+
+// IsCompleteSentence reports whether the sentence ends with a terminator.
+func IsCompleteSentence(s string) bool {
+	s = strings.TrimRight(strings.TrimSpace(s), closingPunctuation)
+	for _, terminator := range sentenceTerminators {
+		if strings.HasSuffix(s, string(terminator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package cleanup
+
+// Levenshtein returns the minimum number of single rune edits needed to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// Similarity returns how similar two strings are, from 0 (completely different) to 1 (identical).
+func Similarity(a, b string) float64 {
+	length := len([]rune(a))
+	if l := len([]rune(b)); l > length {
+		length = l
+	}
+	if length == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(length)
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,37 @@
+package frame
+
+import (
+	"image"
+	"sync"
+	"time"
+)
+
+// Cache keeps the last captured frame for a short time so that close captures reuse it.
+type Cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	frame      image.Image
+	capturedAt time.Time
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl}
+}
+
+// Get returns the cached frame if it is still fresh, or captures a new one.
+// The returned boolean reports whether the cached frame was used.
+func (c *Cache) Get(capture func() (image.Image, error)) (image.Image, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.frame != nil && time.Since(c.capturedAt) < c.ttl {
+		return c.frame, true, nil
+	}
+
+	frame, err := capture()
+	if err != nil {
+		return nil, false, err
+	}
+	c.frame, c.capturedAt = frame, time.Now()
+	return frame, false, nil
+}
@@ -0,0 +1,62 @@
+package frame
+
+import (
+	"image"
+	"image/color"
+)
+
+// colorBits is the number of bits kept per channel when counting colors, to ignore antialiasing and compression noise.
+const colorBits = 3
+
+// TextColor returns the color of the text within rect, sampled on a grid: the background being the most frequent
+// color, the text is the most frequent color clearly differing from it. The boolean is false without such color.
+func TextColor(img image.Image, rect image.Rectangle) (color.RGBA, bool) {
+	rect = rect.Intersect(img.Bounds())
+	if rect.Empty() {
+		return color.RGBA{}, false
+	}
+
+	counts := make(map[[3]uint8]int)
+	for j := 0; j < samples; j++ {
+		for i := 0; i < samples; i++ {
+			r, g, b, _ := img.At(rect.Min.X+i*rect.Dx()/samples, rect.Min.Y+j*rect.Dy()/samples).RGBA()
+			counts[[3]uint8{uint8(r >> (16 - colorBits)), uint8(g >> (16 - colorBits)), uint8(b >> (16 - colorBits))}]++
+		}
+	}
+
+	var background [3]uint8
+	for c, n := range counts {
+		if n > counts[background] {
+			background = c
+		}
+	}
+	var text [3]uint8
+	found := false
+	for c, n := range counts {
+		if distance(c, background) < 2 {
+			continue
+		}
+		if !found || n > counts[text] {
+			text, found = c, true
+		}
+	}
+
+	// Use the middle of the quantized range
+	expand := func(v uint8) uint8 { return v<<(8-colorBits) | 1<<(7-colorBits) }
+	return color.RGBA{R: expand(text[0]), G: expand(text[1]), B: expand(text[2]), A: 0xFF}, found
+}
+
+// distance returns the largest difference between the channels of two quantized colors.
+func distance(a, b [3]uint8) int {
+	d := 0
+	for i := range a {
+		diff := int(a[i]) - int(b[i])
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > d {
+			d = diff
+		}
+	}
+	return d
+}
@@ -0,0 +1,35 @@
+package frame
+
+import (
+	"image"
+	"math"
+)
+
+const samples = 64
+
+// Difference returns how much two frames differ, from 0 (identical) to 1 (completely different).
+// Frames are compared on a sampling grid of their luminance, which is cheap and good enough to detect scene cuts.
+func Difference(a, b image.Image) float64 {
+	boundsA, boundsB := a.Bounds(), b.Bounds()
+	if boundsA.Size() != boundsB.Size() || boundsA.Empty() {
+		return 1
+	}
+
+	var total float64
+	for j := 0; j < samples; j++ {
+		for i := 0; i < samples; i++ {
+			x := i * boundsA.Dx() / samples
+			y := j * boundsA.Dy() / samples
+			la := luminance(a, boundsA.Min.X+x, boundsA.Min.Y+y)
+			lb := luminance(b, boundsB.Min.X+x, boundsB.Min.Y+y)
+			total += math.Abs(la - lb)
+		}
+	}
+	return total / (samples * samples)
+}
+
+// luminance returns the relative luminance of a pixel between 0 and 1.
+func luminance(img image.Image, x, y int) float64 {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xFFFF
+}
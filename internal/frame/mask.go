@@ -0,0 +1,25 @@
+package frame
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Neutral is the color excluded zones are filled with.
+var Neutral = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}
+
+// Mask returns a copy of the image with the zones filled with the given color.
+// Zones are relative to the top-left corner of the image.
+func Mask(img image.Image, zones []image.Rectangle, c color.Color) image.Image {
+	if len(zones) == 0 {
+		return img
+	}
+	bounds := img.Bounds()
+	masked := image.NewRGBA(bounds)
+	draw.Draw(masked, bounds, img, bounds.Min, draw.Src)
+	for _, zone := range zones {
+		draw.Draw(masked, zone.Add(bounds.Min).Intersect(bounds), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	return masked
+}
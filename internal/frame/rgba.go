@@ -0,0 +1,18 @@
+package frame
+
+import (
+	"image"
+	"image/draw"
+)
+
+// RGBA returns img as an RGBA image, with its alpha channel, converting it only when needed.
+// Captured and decoded images are converted so that the whole pipeline works on the same image type.
+func RGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(bounds)
+	draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,56 @@
+package frame
+
+import (
+	"image"
+	"math"
+)
+
+// scrollThreshold is the maximum row difference for two rows to be considered the same content.
+const scrollThreshold = 0.02
+
+// VerticalScroll estimates by how many pixels the content of b is scrolled up compared to a.
+// It returns 0 when the frames are identical or when no scroll explains the difference.
+func VerticalScroll(a, b image.Image) int {
+	boundsA, boundsB := a.Bounds(), b.Bounds()
+	if boundsA.Size() != boundsB.Size() || boundsA.Dy() < 2 {
+		return 0
+	}
+	rowsA, rowsB := rowSignatures(a), rowSignatures(b)
+	if rowsDifference(rowsA, rowsB, 0) < scrollThreshold {
+		return 0
+	}
+
+	best, bestDifference := 0, math.MaxFloat64
+	for dy := 1; dy <= len(rowsA)/2; dy++ {
+		if difference := rowsDifference(rowsA, rowsB, dy); difference < bestDifference {
+			best, bestDifference = dy, difference
+		}
+	}
+	if bestDifference >= scrollThreshold {
+		return 0
+	}
+	return best
+}
+
+// rowSignatures samples the luminance of every row of the image.
+func rowSignatures(img image.Image) [][samples]float64 {
+	bounds := img.Bounds()
+	rows := make([][samples]float64, bounds.Dy())
+	for y := range rows {
+		for i := 0; i < samples; i++ {
+			rows[y][i] = luminance(img, bounds.Min.X+i*bounds.Dx()/samples, bounds.Min.Y+y)
+		}
+	}
+	return rows
+}
+
+// rowsDifference compares the rows of a shifted up by dy with the rows of b.
+func rowsDifference(a, b [][samples]float64, dy int) float64 {
+	var total float64
+	for y := 0; y+dy < len(a); y++ {
+		for i := 0; i < samples; i++ {
+			total += math.Abs(a[y+dy][i] - b[y][i])
+		}
+	}
+	return total / float64((len(a)-dy)*samples)
+}
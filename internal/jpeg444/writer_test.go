@@ -0,0 +1,107 @@
+package jpeg444
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// maxMeanError is the mean error per channel allowed at quality 90.
+const maxMeanError = 8.0
+
+// colorEdges returns an image of colored stripes, as text drawn in color over a background of another one.
+func colorEdges() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			c := color.RGBA{B: 0xC0, A: 0xFF}
+			if x%3 == 1 || y%5 == 2 { // Strokes one pixel wide, not aligned on the subsampled blocks
+				c = color.RGBA{R: 0xFF, G: 0x20, A: 0xFF}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// meanError returns the mean absolute difference of the color channels of the images.
+func meanError(a, b image.Image) float64 {
+	var sum, n float64
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r0, g0, b0, _ := a.At(x, y).RGBA()
+			r1, g1, b1, _ := b.At(x, y).RGBA()
+			for _, d := range []int{int(r0>>8) - int(r1>>8), int(g0>>8) - int(g1>>8), int(b0>>8) - int(b1>>8)} {
+				if d < 0 {
+					d = -d
+				}
+				sum += float64(d)
+				n++
+			}
+		}
+	}
+	return sum / n
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	img := colorEdges()
+
+	var buffer bytes.Buffer
+	if err := Encode(&buffer, img, &Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := jpeg.Decode(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ycbcr, ok := decoded.(*image.YCbCr)
+	if !ok {
+		t.Fatalf("decoded a %T, want an *image.YCbCr", decoded)
+	}
+	if ycbcr.SubsampleRatio != image.YCbCrSubsampleRatio444 {
+		t.Errorf("subsample ratio = %v, want %v", ycbcr.SubsampleRatio, image.YCbCrSubsampleRatio444)
+	}
+	if ycbcr.Bounds() != img.Bounds() {
+		t.Errorf("decoded bounds = %v, want %v", ycbcr.Bounds(), img.Bounds())
+	}
+
+	buffer.Reset()
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	subsampled, err := jpeg.Decode(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The colored strokes are kept, rather than blurred by the chroma subsampling of the standard library
+	full, reduced := meanError(img, decoded), meanError(img, subsampled)
+	if full > maxMeanError {
+		t.Errorf("mean error = %.2f, want at most %.2f", full, maxMeanError)
+	}
+	if full*4 > reduced {
+		t.Errorf("mean error = %.2f, want it well below the %.2f of 4:2:0", full, reduced)
+	}
+}
+
+func TestEncodePartialBlocks(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 17, 9))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 7)
+	}
+
+	// The default quality, on a size that isn't a multiple of the 8x8 blocks
+	var buffer bytes.Buffer
+	if err := Encode(&buffer, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := jpeg.Decode(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Bounds() != img.Bounds() {
+		t.Errorf("decoded bounds = %v, want %v", decoded.Bounds(), img.Bounds())
+	}
+}
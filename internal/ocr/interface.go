@@ -0,0 +1,21 @@
+package ocr
+
+import (
+	"context"
+	"image"
+
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+)
+
+// Options tune a text detection.
+type Options struct {
+	// LanguageHints are optional and improve accuracy for some languages.
+	LanguageHints []string
+	// Sparse detects scattered text, such as in-game labels, rather than dense documents.
+	Sparse bool
+}
+
+type OCR interface {
+	DetectText(ctx context.Context, img image.Image, options Options) (*visionpb.TextAnnotation, error)
+	Close()
+}
@@ -0,0 +1,21 @@
+package ocr
+
+import visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+
+// DominantLanguage returns the language detected with the highest total confidence across the pages of the annotation.
+func DominantLanguage(annotation *visionpb.TextAnnotation) string {
+	confidences := make(map[string]float32)
+	for _, page := range annotation.GetPages() {
+		for _, language := range page.GetProperty().GetDetectedLanguages() {
+			confidences[language.LanguageCode] += language.Confidence
+		}
+	}
+
+	var dominant string
+	for language, confidence := range confidences {
+		if dominant == "" || confidence > confidences[dominant] || (confidence == confidences[dominant] && language < dominant) {
+			dominant = language
+		}
+	}
+	return dominant
+}
@@ -0,0 +1,44 @@
+// Package ocrtest provides a fake OCR for the tests of the pipeline.
+package ocrtest
+
+import (
+	"context"
+	"image"
+	"sync"
+
+	"github.com/bquenin/interpreter/internal/ocr"
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fake is an OCR for the tests, detecting the same annotation in every image and recording the options it is given.
+type Fake struct {
+	Annotation *visionpb.TextAnnotation
+	Err        error // Returned instead of the annotation when set
+
+	mu      sync.Mutex
+	options []ocr.Options
+}
+
+// DetectText returns a copy of the annotation, as the pipeline modifies the annotations it filters.
+func (f *Fake) DetectText(ctx context.Context, img image.Image, options ocr.Options) (*visionpb.TextAnnotation, error) {
+	f.mu.Lock()
+	f.options = append(f.options, options)
+	f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Annotation == nil {
+		return nil, nil
+	}
+	return proto.Clone(f.Annotation).(*visionpb.TextAnnotation), nil
+}
+
+// Options returns the options of every detection, in order.
+func (f *Fake) Options() []ocr.Options {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ocr.Options(nil), f.options...)
+}
+
+func (f *Fake) Close() {}
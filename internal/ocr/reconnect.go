@@ -0,0 +1,67 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+	"image"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+)
+
+// Reconnecting recreates its OCR client after a number of consecutive failures, as long sessions may end up with a
+// stale connection failing every call.
+type Reconnecting struct {
+	mu        sync.Mutex
+	connect   func() (OCR, error)
+	ocr       OCR
+	threshold int
+	failures  int
+}
+
+func NewReconnecting(connect func() (OCR, error), threshold int) (*Reconnecting, error) {
+	ocr, err := connect()
+	if err != nil {
+		return nil, err
+	}
+	return &Reconnecting{connect: connect, ocr: ocr, threshold: threshold}, nil
+}
+
+func (r *Reconnecting) DetectText(ctx context.Context, img image.Image, options Options) (*visionpb.TextAnnotation, error) {
+	r.mu.Lock()
+	ocr := r.ocr
+	r.mu.Unlock()
+
+	annotation, err := ocr.DetectText(ctx, img, options)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case err == nil:
+		r.failures = 0
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+	default:
+		r.failures++
+		if r.failures >= r.threshold && ocr == r.ocr {
+			r.reconnect()
+		}
+	}
+	return annotation, err
+}
+
+func (r *Reconnecting) reconnect() {
+	log.Warn().Msgf("OCR failed %d times in a row, reconnecting", r.failures)
+	ocr, err := r.connect()
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to reconnect OCR, keeping the current client")
+		return
+	}
+	r.ocr.Close()
+	r.ocr, r.failures = ocr, 0
+}
+
+func (r *Reconnecting) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ocr.Close()
+}
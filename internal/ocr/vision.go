@@ -0,0 +1,236 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/vision/apiv1"
+	"github.com/bquenin/interpreter/internal/jpeg444"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/image/draw"
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+)
+
+const (
+	quality    = 85
+	minQuality = 40
+)
+
+// Latency control: the average latency is smoothed over the last calls, and the scale adjusted by a step each call
+// the average is outside of the target band.
+const (
+	latencySmoothing = 0.3
+	latencyBand      = 0.25
+	scaleStep        = 0.1
+)
+
+// Formats of the image sent to Vision
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+)
+
+// Chroma subsamplings of the JPEG images sent to Vision
+const (
+	Subsampling420 = "4:2:0"
+	Subsampling444 = "4:4:4"
+)
+
+type Vision struct {
+	client       *vision.ImageAnnotatorClient
+	maxImageSize int
+	format       string
+	subsampling  string
+
+	mu            sync.Mutex
+	scale         float64
+	maxScale      float64
+	minScale      float64
+	latencyTarget time.Duration
+	latency       time.Duration
+}
+
+// NewVision returns a Vision client. Images are resized by scale, between 0 and 1, before being sent.
+// JPEG images are encoded with the given chroma subsampling.
+func NewVision(maxImageSize int, format, subsampling string, scale float64) (*Vision, error) {
+	client, err := vision.NewImageAnnotatorClient(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Vision{client: client, maxImageSize: maxImageSize, format: format, subsampling: subsampling, scale: scale, maxScale: scale}, nil
+}
+
+// AdaptScale makes the scale follow the latency of Vision: it's lowered down to minScale while the calls take longer
+// than the target, and raised back up to the scale Vision was created with while they're faster.
+func (v *Vision) AdaptScale(target time.Duration, minScale float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.latencyTarget, v.minScale = target, minScale
+}
+
+// observe records the latency of a call and adjusts the scale accordingly.
+func (v *Vision) observe(latency time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.latencyTarget <= 0 {
+		return
+	}
+	if v.latency == 0 {
+		v.latency = latency
+	}
+	v.latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(v.latency))
+
+	scale := v.scale
+	switch {
+	case v.latency > time.Duration(float64(v.latencyTarget)*(1+latencyBand)):
+		scale = math.Max(v.minScale, scale-scaleStep)
+	case v.latency < time.Duration(float64(v.latencyTarget)*(1-latencyBand)):
+		scale = math.Min(v.maxScale, scale+scaleStep)
+	}
+	if scale != v.scale {
+		log.Info().Msgf("average OCR latency is %s for a %s target: scaling images by %.2f", v.latency.Round(time.Millisecond), v.latencyTarget, scale)
+		v.scale = scale
+	}
+}
+
+// DetectText detects the text of the image. The image sent to Vision is a copy, downscaled by the scale or to fit the
+// maximum image size: img itself is left untouched and the bounding boxes are scaled back to its resolution.
+func (v *Vision) DetectText(ctx context.Context, img image.Image, options Options) (*visionpb.TextAnnotation, error) {
+	v.mu.Lock()
+	scale := v.scale
+	v.mu.Unlock()
+	sent := img
+	if scale > 0 && scale < 1 {
+		sent = resize(img, scale)
+	}
+
+	// Encode to JPEG or PNG, the latter keeping the alpha channel
+	buffer, sent, err := v.encode(sent)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	annotation, err := v.detect(ctx, buffer, options)
+	if err != nil {
+		return nil, err
+	}
+	v.observe(time.Since(start))
+	if width := sent.Bounds().Dx(); width > 0 && width != img.Bounds().Dx() {
+		scaleAnnotation(annotation, float64(img.Bounds().Dx())/float64(width))
+	}
+	return annotation, nil
+}
+
+func (v *Vision) detect(ctx context.Context, buffer *bytes.Buffer, options Options) (*visionpb.TextAnnotation, error) {
+	// Create image
+	visionImage, err := vision.NewImageFromReader(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract text from image
+	var imageContext *visionpb.ImageContext
+	if len(options.LanguageHints) > 0 {
+		imageContext = &visionpb.ImageContext{LanguageHints: options.LanguageHints}
+	}
+	if !options.Sparse {
+		return v.client.DetectDocumentText(ctx, visionImage, imageContext)
+	}
+
+	response, err := v.client.AnnotateImage(ctx, &visionpb.AnnotateImageRequest{
+		Image:        visionImage,
+		ImageContext: imageContext,
+		Features:     []*visionpb.Feature{{Type: visionpb.Feature_TEXT_DETECTION}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("vision error: %s", response.Error.Message)
+	}
+	return response.FullTextAnnotation, nil
+}
+
+// encode encodes the image, lowering the JPEG quality then the resolution until it fits the maximum image size.
+// It returns the image encoded as well, which is smaller than img when downscaled.
+func (v *Vision) encode(img image.Image) (*bytes.Buffer, image.Image, error) {
+	q := quality
+	for {
+		var buffer bytes.Buffer
+		var err error
+		switch {
+		case v.format == FormatPNG:
+			err = png.Encode(&buffer, img)
+		case v.subsampling == Subsampling444: // The standard library only encodes 4:2:0
+			err = jpeg444.Encode(&buffer, img, &jpeg444.Options{Quality: q})
+		default:
+			err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: q})
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if v.maxImageSize <= 0 || buffer.Len() <= v.maxImageSize || img.Bounds().Dx() < 2 || img.Bounds().Dy() < 2 {
+			return &buffer, img, nil
+		}
+
+		if v.format != FormatPNG && q > minQuality {
+			q -= 15
+			log.Info().Msgf("encoded image is %d bytes, over the %d bytes limit: lowering quality to %d", buffer.Len(), v.maxImageSize, q)
+			continue
+		}
+		img = resize(img, 0.5)
+		log.Info().Msgf("encoded image is %d bytes, over the %d bytes limit: downscaling to %v", buffer.Len(), v.maxImageSize, img.Bounds().Size())
+	}
+}
+
+// resize returns a copy of the image with its resolution multiplied by scale.
+func resize(img image.Image, scale float64) image.Image {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, int(float64(bounds.Dx())*scale), int(float64(bounds.Dy())*scale)))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// scaleAnnotation multiplies the coordinates of all the bounding boxes of the annotation by factor.
+func scaleAnnotation(annotation *visionpb.TextAnnotation, factor float64) {
+	if annotation == nil {
+		return
+	}
+	scale := func(box *visionpb.BoundingPoly) {
+		if box == nil {
+			return
+		}
+		for _, vertex := range box.Vertices {
+			vertex.X = int32(float64(vertex.X) * factor)
+			vertex.Y = int32(float64(vertex.Y) * factor)
+		}
+	}
+	for _, page := range annotation.Pages {
+		page.Width = int32(float64(page.Width) * factor)
+		page.Height = int32(float64(page.Height) * factor)
+		for _, block := range page.Blocks {
+			scale(block.BoundingBox)
+			for _, paragraph := range block.Paragraphs {
+				scale(paragraph.BoundingBox)
+				for _, word := range paragraph.Words {
+					scale(word.BoundingBox)
+					for _, symbol := range word.Symbols {
+						scale(symbol.BoundingBox)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (v *Vision) Close() {
+	_ = v.client.Close()
+}
@@ -0,0 +1,103 @@
+package ocr
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	visionpb "google.golang.org/genproto/googleapis/cloud/vision/v1"
+)
+
+// box returns the bounding box of the rectangle.
+func box(r image.Rectangle) *visionpb.BoundingPoly {
+	return &visionpb.BoundingPoly{Vertices: []*visionpb.Vertex{
+		{X: int32(r.Min.X), Y: int32(r.Min.Y)},
+		{X: int32(r.Max.X), Y: int32(r.Min.Y)},
+		{X: int32(r.Max.X), Y: int32(r.Max.Y)},
+		{X: int32(r.Min.X), Y: int32(r.Max.Y)},
+	}}
+}
+
+func TestScaleAnnotation(t *testing.T) {
+	annotation := &visionpb.TextAnnotation{Pages: []*visionpb.Page{{
+		Width:  320,
+		Height: 240,
+		Blocks: []*visionpb.Block{{
+			BoundingBox: box(image.Rect(10, 20, 110, 60)),
+			Paragraphs: []*visionpb.Paragraph{{
+				BoundingBox: box(image.Rect(10, 20, 110, 60)),
+				Words: []*visionpb.Word{{
+					BoundingBox: box(image.Rect(10, 20, 50, 60)),
+					Symbols:     []*visionpb.Symbol{{Text: "a", BoundingBox: box(image.Rect(10, 20, 30, 60))}, {Text: "b"}},
+				}},
+			}},
+		}},
+	}}}
+
+	// Detected on a copy downscaled by 0.5, mapped back to the resolution of the captured image
+	scaleAnnotation(annotation, 2)
+
+	page := annotation.Pages[0]
+	if page.Width != 640 || page.Height != 480 {
+		t.Errorf("page is %dx%d, want 640x480", page.Width, page.Height)
+	}
+	block := page.Blocks[0]
+	word := block.Paragraphs[0].Words[0]
+	for name, test := range map[string]struct {
+		box  *visionpb.BoundingPoly
+		want image.Rectangle
+	}{
+		"block":     {block.BoundingBox, image.Rect(20, 40, 220, 120)},
+		"paragraph": {block.Paragraphs[0].BoundingBox, image.Rect(20, 40, 220, 120)},
+		"word":      {word.BoundingBox, image.Rect(20, 40, 100, 120)},
+		"symbol":    {word.Symbols[0].BoundingBox, image.Rect(20, 40, 60, 120)},
+	} {
+		vertices := test.box.Vertices
+		if got := image.Rect(int(vertices[0].X), int(vertices[0].Y), int(vertices[2].X), int(vertices[2].Y)); got != test.want {
+			t.Errorf("%s box = %v, want %v", name, got, test.want)
+		}
+	}
+	if word.Symbols[1].BoundingBox != nil {
+		t.Error("symbol without bounding box got one")
+	}
+	scaleAnnotation(nil, 2) // Nothing detected
+}
+
+func TestResizeKeepsOriginal(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+	for x := 0; x < 64; x++ {
+		img.Set(x, 5, color.RGBA{R: 0xFF, A: 0xFF})
+	}
+	pixels := append([]uint8(nil), img.Pix...)
+
+	resized := resize(img, 0.5)
+
+	if size := resized.Bounds().Size(); size != image.Pt(32, 16) {
+		t.Errorf("resized to %v, want %v", size, image.Pt(32, 16))
+	}
+	if img.Bounds() != image.Rect(0, 0, 64, 32) || string(img.Pix) != string(pixels) {
+		t.Error("resizing modified the original image, displayed at full resolution")
+	}
+}
+
+func TestEncodeDownscalesCopy(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	random := rand.New(rand.NewSource(1))
+	random.Read(img.Pix) // Noise, not to compress well
+	v := &Vision{maxImageSize: 40_000, format: FormatPNG}
+
+	buffer, sent, err := v.encode(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buffer.Len() > v.maxImageSize {
+		t.Errorf("encoded image is %d bytes, over the %d bytes limit", buffer.Len(), v.maxImageSize)
+	}
+	if sent.Bounds().Dx() >= img.Bounds().Dx() {
+		t.Errorf("sent image is %v, want it downscaled", sent.Bounds())
+	}
+	if img.Bounds() != image.Rect(0, 0, 256, 256) {
+		t.Error("encoding modified the original image")
+	}
+}
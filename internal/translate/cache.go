@@ -0,0 +1,75 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Cached remembers the last translations of a translator, the least recently used ones being forgotten first.
+// The same text is only translated once as long as it is remembered.
+type Cached struct {
+	mu           sync.Mutex
+	translator   Translator
+	size         int
+	translations map[string]string
+	order        []string // From the least to the most recently used
+}
+
+func NewCached(translator Translator, size int) (*Cached, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid cache size %d, it must be positive", size)
+	}
+	return &Cached{translator: translator, size: size, translations: make(map[string]string, size)}, nil
+}
+
+func (c *Cached) Translate(ctx context.Context, toTranslate string) (string, error) {
+	c.mu.Lock()
+	translation, ok := c.translations[toTranslate]
+	if ok {
+		c.touch(toTranslate)
+	}
+	c.mu.Unlock()
+	if ok {
+		return translation, nil
+	}
+
+	translation, err := c.translator.Translate(ctx, toTranslate)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.translations[toTranslate]; ok {
+		c.touch(toTranslate)
+	} else {
+		if len(c.order) == c.size {
+			delete(c.translations, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, toTranslate)
+	}
+	c.translations[toTranslate] = translation
+	return translation, nil
+}
+
+// touch marks the text as the most recently used.
+func (c *Cached) touch(toTranslate string) {
+	for i, text := range c.order {
+		if text == toTranslate {
+			copy(c.order[i:], c.order[i+1:])
+			c.order[len(c.order)-1] = toTranslate
+			return
+		}
+	}
+}
+
+// Unwrap returns the cached translator.
+func (c *Cached) Unwrap() Translator {
+	return c.translator
+}
+
+func (c *Cached) Close() {
+	c.translator.Close()
+}
@@ -0,0 +1,90 @@
+package translate
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+func TestCached(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		texts []string
+		calls []string
+	}{
+		{"repeated text", 2, []string{"a", "a", "a"}, []string{"a"}},
+		{"distinct texts", 2, []string{"a", "b", "a", "b"}, []string{"a", "b"}},
+		{"least recently used forgotten", 2, []string{"a", "b", "c", "a"}, []string{"a", "b", "c", "a"}},
+		{"recently used kept", 2, []string{"a", "b", "a", "c", "a"}, []string{"a", "b", "c"}},
+		{"single entry", 1, []string{"a", "b", "a"}, []string{"a", "b", "a"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &translatetest.Fake{Prefix: "en:"}
+			cached, err := NewCached(fake, test.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, text := range test.texts {
+				translation, err := cached.Translate(context.Background(), text)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if translation != "en:"+text {
+					t.Errorf("Translate(%q) = %q, want %q", text, translation, "en:"+text)
+				}
+			}
+			if calls := fake.Calls(); !reflect.DeepEqual(calls, test.calls) {
+				t.Errorf("translator called with %q, want %q", calls, test.calls)
+			}
+		})
+	}
+}
+
+func TestCachedErrorNotRemembered(t *testing.T) {
+	fake := &translatetest.Fake{Err: &Error{ErrNetwork, context.DeadlineExceeded}}
+	cached, err := NewCached(fake, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cached.Translate(context.Background(), "a"); err == nil {
+			t.Fatal("Translate succeeded, want an error")
+		}
+	}
+	if calls := len(fake.Calls()); calls != 2 {
+		t.Errorf("translator called %d times, want 2", calls)
+	}
+}
+
+func TestNewCachedInvalidSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		if _, err := NewCached(&translatetest.Fake{}, size); err == nil {
+			t.Errorf("NewCached(%d) succeeded, want an error", size)
+		}
+	}
+}
+
+func TestCachedUnwrap(t *testing.T) {
+	cached, err := NewCached(&translatetest.Fake{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := AsUsageReporter(cached); ok {
+		t.Error("cached translator reports its usage, but the wrapped one doesn't")
+	}
+	if _, ok := AsHealthChecker(cached); ok {
+		t.Error("cached translator checks its health, but the wrapped one doesn't")
+	}
+
+	deepL := &DeepL{}
+	if cached, err = NewCached(deepL, 1); err != nil {
+		t.Fatal(err)
+	}
+	if reporter, ok := AsUsageReporter(cached); !ok || reporter != deepL {
+		t.Errorf("AsUsageReporter() = %v, %t, want the wrapped translator", reporter, ok)
+	}
+}
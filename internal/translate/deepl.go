@@ -1,28 +1,44 @@
 package translate
 
 import (
+	"context"
 	"encoding/json"
-	"golang.org/x/text/language"
+	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/text/language"
 )
 
 const (
-	apiURL = "https://api-free.deepl.com/v2/translate"
+	freeAPIURL = "https://api-free.deepl.com/v2"
+	proAPIURL  = "https://api.deepl.com/v2"
 )
 
 type DeepL struct {
 	target            language.Tag
 	authenticationKey string
+	maxRetries        int
+	tagHandling       string
+	baseURL           string // Overrides the API endpoint matching the authentication key, for the tests
 }
 
-func NewDeepL(translateTo, authenticationKey string) (*DeepL, error) {
+func NewDeepL(translateTo, authenticationKey string, maxRetries int, tagHandling string) (*DeepL, error) {
 	language, err := language.Parse(translateTo)
 	if err != nil {
 		return nil, err
 	}
-	return &DeepL{language, authenticationKey}, nil
+	switch tagHandling {
+	case "", "html", "xml":
+	default:
+		return nil, fmt.Errorf("unsupported deepL tag handling: %s", tagHandling)
+	}
+	return &DeepL{target: language, authenticationKey: authenticationKey, maxRetries: maxRetries, tagHandling: tagHandling}, nil
 }
 
 type DeepLResponse struct {
@@ -34,34 +50,147 @@ type Translations struct {
 	Text                   string `json:"text"`
 }
 
-func (d *DeepL) Translate(source string) (string, error) {
-	u, _ := url.Parse(apiURL)
+func (d *DeepL) Translate(ctx context.Context, source string) (string, error) {
+	u, _ := url.Parse(d.apiURL() + "/translate")
 
 	urlData := url.Values{}
 	urlData.Set("auth_key", d.authenticationKey)
 	urlData.Set("target_lang", d.target.String())
 	urlData.Set("text", source)
+	if d.tagHandling != "" {
+		urlData.Set("tag_handling", d.tagHandling)
+	}
 
 	client := &http.Client{}
-	r, _ := http.NewRequest(http.MethodPost, u.String(), strings.NewReader(urlData.Encode())) // URL-encoded payload
-	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	for attempt := 0; ; attempt++ {
+		r, _ := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(urlData.Encode())) // URL-encoded payload
+		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := client.Do(r)
-	defer resp.Body.Close()
+		resp, err := client.Do(r)
+		if err != nil {
+			return "", &Error{ErrNetwork, err}
+		}
+
+		// Rate limited: wait as instructed by DeepL and try again
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < d.maxRetries {
+			_ = resp.Body.Close()
+			select {
+			case <-time.After(retryDelay(resp.Header.Get("Retry-After"), attempt)):
+				continue
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusBadRequest {
+			return "", badRequestError(resp.Body)
+		}
+		if err := statusError(resp.StatusCode); err != nil {
+			return "", err
+		}
+
+		var deepL DeepLResponse
+		if err := json.NewDecoder(resp.Body).Decode(&deepL); err != nil {
+			return "", err
+		}
+
+		if len(deepL.Translations) == 0 {
+			return "", nil
+		}
+
+		return deepL.Translations[0].Text, nil
+	}
+}
+
+// apiURL returns the API endpoint matching the authentication key: free account keys end with ":fx".
+func (d *DeepL) apiURL() string {
+	if d.baseURL != "" {
+		return d.baseURL
+	}
+	if strings.HasSuffix(d.authenticationKey, ":fx") {
+		return freeAPIURL
+	}
+	return proAPIURL
+}
+
+type DeepLUsage struct {
+	CharacterCount int64 `json:"character_count"`
+	CharacterLimit int64 `json:"character_limit"`
+}
+
+// Usage returns the characters translated and the character limit of the current billing period.
+func (d *DeepL) Usage(ctx context.Context) (*Usage, error) {
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, d.apiURL()+"/usage", nil)
+	r.Header.Add("Authorization", "DeepL-Auth-Key "+d.authenticationKey)
+
+	resp, err := http.DefaultClient.Do(r)
 	if err != nil {
-		return "", err
+		return nil, &Error{ErrNetwork, err}
+	}
+	defer resp.Body.Close()
+	if err := statusError(resp.StatusCode); err != nil {
+		return nil, err
 	}
 
-	var deepL DeepLResponse
-	if err := json.NewDecoder(resp.Body).Decode(&deepL); err != nil {
-		return "", err
+	var usage DeepLUsage
+	if err := json.NewDecoder(resp.Body).Decode(&usage); err != nil {
+		return nil, err
+	}
+	return &Usage{Used: usage.CharacterCount, Limit: usage.CharacterLimit}, nil
+}
+
+// HealthCheck verifies the authentication key against the usage endpoint, which doesn't consume any quota.
+func (d *DeepL) HealthCheck(ctx context.Context) error {
+	_, err := d.Usage(ctx)
+	return err
+}
+
+// statusError maps the DeepL error status codes to translation errors, the other non-2xx ones being plain errors.
+func statusError(statusCode int) error {
+	switch statusCode {
+	case http.StatusForbidden:
+		return &Error{ErrAuth, fmt.Errorf("deepL returned status %d, please check your authentication key", statusCode)}
+	case 456: // Quota exceeded
+		return &Error{ErrQuota, fmt.Errorf("deepL returned status %d, the character limit has been reached", statusCode)}
+	case http.StatusTooManyRequests:
+		return &Error{ErrRateLimited, fmt.Errorf("deepL returned status %d, too many requests", statusCode)}
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		return fmt.Errorf("deepL returned status %d", statusCode)
 	}
+	return nil
+}
 
-	if len(deepL.Translations) == 0 {
-		return "", nil
+// badRequestError returns the error of a bad request along with the message returned, an unsupported target language
+// being reported as such.
+func badRequestError(body io.Reader) error {
+	var deepL struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(body).Decode(&deepL); err != nil || deepL.Message == "" {
+		return fmt.Errorf("deepL returned status %d", http.StatusBadRequest)
 	}
+	err := fmt.Errorf("deepL returned status %d: %s", http.StatusBadRequest, deepL.Message)
+	if strings.Contains(deepL.Message, "target_lang") { // Value for 'target_lang' not supported.
+		return &Error{ErrUnsupportedLanguage, err}
+	}
+	return err
+}
 
-	return deepL.Translations[0].Text, nil
+// retryDelay returns how long to wait before retrying, based on the Retry-After header if any,
+// exponential backoff otherwise. Jitter is added to avoid retrying in lockstep.
+func retryDelay(retryAfter string, attempt int) time.Duration {
+	delay := time.Second << attempt
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		delay = time.Until(date)
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay/2)+int64(time.Millisecond*100)))
 }
 
 func (d *DeepL) Close() {}
@@ -0,0 +1,163 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestDeepL returns a DeepL translator sending its requests to the given handler.
+func newTestDeepL(t *testing.T, maxRetries int, handler http.HandlerFunc) *DeepL {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	d, err := NewDeepL("en", "key", maxRetries, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.baseURL = server.URL
+	return d
+}
+
+func TestDeepLRetriesRateLimited(t *testing.T) {
+	var requests int32
+	d := newTestDeepL(t, 3, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintf(w, `{"translations": [{"detected_source_language": "JA", "text": "hello %s"}]}`, r.FormValue("target_lang"))
+	})
+
+	start := time.Now()
+	translation, err := d.Translate(context.Background(), "こんにちは")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation != "hello en" {
+		t.Errorf("Translate() = %q, want %q", translation, "hello en")
+	}
+	if requests := atomic.LoadInt32(&requests); requests != 2 {
+		t.Errorf("%d requests sent, want 2", requests)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, before the Retry-After delay of 1s", elapsed)
+	}
+}
+
+func TestDeepLRetriesExhausted(t *testing.T) {
+	var requests int32
+	d := newTestDeepL(t, 1, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	if _, err := d.Translate(context.Background(), "a"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Translate() error = %v, want %v", err, ErrRateLimited)
+	}
+	if requests := atomic.LoadInt32(&requests); requests != 2 {
+		t.Errorf("%d requests sent, want 2", requests)
+	}
+}
+
+func TestDeepLRetryCancelled(t *testing.T) {
+	d := newTestDeepL(t, 10, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := d.Translate(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Translate() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Translate() returned after %s, long after the context was cancelled", elapsed)
+	}
+}
+
+func TestDeepLUnsupportedLanguage(t *testing.T) {
+	tests := []struct {
+		body string
+		kind error
+	}{
+		{`{"message": "Value for 'target_lang' not supported."}`, ErrUnsupportedLanguage},
+		{`{"message": "Parameter 'text' not specified."}`, nil},
+		{``, nil},
+	}
+	for _, test := range tests {
+		d := newTestDeepL(t, 0, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(test.body))
+		})
+		_, err := d.Translate(context.Background(), "a")
+		if err == nil {
+			t.Fatalf("Translate() succeeded with body %q, want an error", test.body)
+		}
+		if errors.Is(err, ErrUnsupportedLanguage) != (test.kind != nil) {
+			t.Errorf("Translate() error = %v with body %q, want kind %v", err, test.body, test.kind)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		retryAfter string
+		attempt    int
+		min, max   time.Duration
+	}{
+		{"3", 0, 3 * time.Second, 4600 * time.Millisecond},
+		{"0", 5, 0, 100 * time.Millisecond},
+		{"", 0, time.Second, 1600 * time.Millisecond},
+		{"", 2, 4 * time.Second, 6100 * time.Millisecond},
+		{"soon", 1, 2 * time.Second, 3100 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if delay := retryDelay(test.retryAfter, test.attempt); delay < test.min || delay >= test.max {
+			t.Errorf("retryDelay(%q, %d) = %s, want between %s and %s", test.retryAfter, test.attempt, delay, test.min, test.max)
+		}
+	}
+}
+
+func TestDeepLStatusErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		kind   error // nil for a plain error
+	}{
+		{http.StatusForbidden, ErrAuth},
+		{456, ErrQuota},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, nil},
+		{http.StatusServiceUnavailable, nil},
+		{http.StatusNotFound, nil},
+		{http.StatusBadRequest, nil},
+	}
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.status), func(t *testing.T) {
+			d := newTestDeepL(t, 0, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+			})
+			_, translateErr := d.Translate(context.Background(), "a")
+			_, usageErr := d.Usage(context.Background())
+			for name, err := range map[string]error{"Translate": translateErr, "Usage": usageErr} {
+				if err == nil {
+					t.Fatalf("%s() succeeded on status %d, want an error", name, test.status)
+				}
+				for _, kind := range errorKinds {
+					if errors.Is(err, kind) != (kind == test.kind) {
+						t.Errorf("%s() error = %v on status %d, want kind %v", name, err, test.status, test.kind)
+					}
+				}
+			}
+		})
+	}
+}
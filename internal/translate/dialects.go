@@ -0,0 +1,69 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Dialects translates to the first of several variants of a language the provider supports, such as pt-BR then pt-PT.
+// A variant the provider reports as an unsupported target language is skipped for the rest of the session, any other
+// error being returned as is.
+type Dialects struct {
+	mu          sync.Mutex
+	targets     []string
+	translators []Translator
+	current     int
+	used        int
+}
+
+// NewDialects returns a translator trying each target in order, translators being built for the targets of the same index.
+func NewDialects(targets []string, translators []Translator) *Dialects {
+	return &Dialects{targets: targets, translators: translators, used: -1}
+}
+
+func (d *Dialects) Translate(ctx context.Context, toTranslate string) (string, error) {
+	d.mu.Lock()
+	start := d.current
+	d.mu.Unlock()
+
+	var err error
+	for i := start; i < len(d.translators); i++ {
+		var translation string
+		translation, err = d.translators[i].Translate(ctx, toTranslate)
+		if err == nil {
+			d.mu.Lock()
+			if d.used != i {
+				log.Info().Msgf("translating to %s", d.targets[i])
+			}
+			d.current, d.used = i, i
+			d.mu.Unlock()
+			return translation, nil
+		}
+		if !errors.Is(err, ErrUnsupportedLanguage) || ctx.Err() != nil {
+			return "", err
+		}
+		if i < len(d.translators)-1 {
+			log.Warn().Err(err).Msgf("unable to translate to %s, trying %s", d.targets[i], d.targets[i+1])
+			d.mu.Lock()
+			d.current = i + 1
+			d.mu.Unlock()
+		}
+	}
+	return "", err
+}
+
+// Unwrap returns the translator of the variant currently used.
+func (d *Dialects) Unwrap() Translator {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.translators[d.current]
+}
+
+func (d *Dialects) Close() {
+	for _, translator := range d.translators {
+		translator.Close()
+	}
+}
@@ -0,0 +1,65 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+func TestDialects(t *testing.T) {
+	unsupported := &Error{ErrUnsupportedLanguage, errors.New("target_lang not supported")}
+	tests := []struct {
+		name    string
+		err     error // Returned by the primary dialect
+		want    string
+		wantErr error
+		demoted bool
+	}{
+		{"supported", nil, "pt-BR:a", nil, false},
+		{"unsupported", unsupported, "pt-PT:a", nil, true},
+		{"network", &Error{ErrNetwork, errors.New("timeout")}, "", ErrNetwork, false},
+		{"rate limited", &Error{ErrRateLimited, errors.New("slow down")}, "", ErrRateLimited, false},
+		{"server error", errors.New("deepL returned status 503"), "", nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			primary, secondary := &translatetest.Fake{Prefix: "pt-BR:", Err: test.err}, &translatetest.Fake{Prefix: "pt-PT:"}
+			dialects := NewDialects([]string{"pt-BR", "pt-PT"}, []Translator{primary, secondary})
+
+			translation, err := dialects.Translate(context.Background(), "a")
+			if (err != nil) != (test.want == "") || (test.wantErr != nil && !errors.Is(err, test.wantErr)) {
+				t.Fatalf("Translate() error = %v, want %v", err, test.wantErr)
+			}
+			if translation != test.want {
+				t.Errorf("Translate() = %q, want %q", translation, test.want)
+			}
+
+			// The primary dialect is only tried again if it wasn't demoted
+			primary.Err = nil
+			if _, err := dialects.Translate(context.Background(), "b"); err != nil {
+				t.Fatal(err)
+			}
+			want := []string{"a", "b"}
+			if test.demoted {
+				want = []string{"a"}
+			}
+			if calls := primary.Calls(); !reflect.DeepEqual(calls, want) {
+				t.Errorf("primary dialect called with %q, want %q", calls, want)
+			}
+			if current := dialects.Unwrap(); (current == secondary) != test.demoted {
+				t.Errorf("Unwrap() returned the other dialect, demoted %t", test.demoted)
+			}
+		})
+	}
+}
+
+func TestDialectsAllUnsupported(t *testing.T) {
+	unsupported := &Error{ErrUnsupportedLanguage, errors.New("target_lang not supported")}
+	dialects := NewDialects([]string{"pt-BR", "pt-PT"}, []Translator{&translatetest.Fake{Err: unsupported}, &translatetest.Fake{Err: unsupported}})
+	if _, err := dialects.Translate(context.Background(), "a"); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("Translate() error = %v, want %v", err, ErrUnsupportedLanguage)
+	}
+}
@@ -0,0 +1,43 @@
+package translate
+
+import (
+	"errors"
+	"net"
+)
+
+// Kinds of translation errors, to be checked with errors.Is.
+var (
+	ErrAuth        = errors.New("authentication failed")
+	ErrQuota       = errors.New("quota exceeded")
+	ErrRateLimited = errors.New("rate limited")
+	ErrNetwork     = errors.New("network error")
+	// ErrUnsupportedLanguage is returned when the provider doesn't translate to the target language.
+	ErrUnsupportedLanguage = errors.New("unsupported target language")
+)
+
+// Error is a translation error of a known kind, wrapping the error returned by the provider.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// wrapNetworkError marks the transport errors, such as timeouts or connections refused, as network errors.
+func wrapNetworkError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &Error{ErrNetwork, err}
+	}
+	return err
+}
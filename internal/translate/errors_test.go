@@ -0,0 +1,27 @@
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errorKinds = []error{ErrAuth, ErrQuota, ErrRateLimited, ErrNetwork, ErrUnsupportedLanguage}
+
+func TestError(t *testing.T) {
+	cause := errors.New("deepL returned status 456")
+	err := fmt.Errorf("unable to translate: %w", &Error{ErrQuota, cause})
+
+	if !errors.Is(err, ErrQuota) {
+		t.Errorf("%v is not %v", err, ErrQuota)
+	}
+	if errors.Is(err, ErrAuth) {
+		t.Errorf("%v is %v", err, ErrAuth)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("%v doesn't wrap %v", err, cause)
+	}
+	if want := "unable to translate: quota exceeded: deepL returned status 456"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,44 @@
+package translate
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Fallback tries its translators in order until one succeeds, each one translating to its own target language.
+type Fallback struct {
+	translators []Translator
+}
+
+func NewFallback(translators ...Translator) *Fallback {
+	return &Fallback{translators}
+}
+
+func (f *Fallback) Translate(ctx context.Context, toTranslate string) (string, error) {
+	var err error
+	for i, translator := range f.translators {
+		var translation string
+		if translation, err = translator.Translate(ctx, toTranslate); err == nil {
+			return translation, nil
+		}
+		if ctx.Err() != nil { // No time left for the next translators
+			return "", err
+		}
+		if i < len(f.translators)-1 {
+			log.Warn().Err(err).Msgf("translator %d failed, falling back to the next one", i+1)
+		}
+	}
+	return "", err
+}
+
+// Unwrap returns the first translator, the one used as long as it succeeds.
+func (f *Fallback) Unwrap() Translator {
+	return f.translators[0]
+}
+
+func (f *Fallback) Close() {
+	for _, translator := range f.translators {
+		translator.Close()
+	}
+}
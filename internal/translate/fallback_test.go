@@ -0,0 +1,48 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+func TestFallback(t *testing.T) {
+	failing := errors.New("unsupported")
+	tests := []struct {
+		name        string
+		translators []*translatetest.Fake
+		want        string
+		wantErr     bool
+	}{
+		{"first succeeds", []*translatetest.Fake{{Prefix: "en:"}, {Prefix: "fr:"}}, "en:a", false},
+		{"first fails", []*translatetest.Fake{{Err: failing}, {Prefix: "fr:"}}, "fr:a", false},
+		{"all fail", []*translatetest.Fake{{Err: failing}, {Err: failing}}, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			translators := make([]Translator, 0, len(test.translators))
+			for _, translator := range test.translators {
+				translators = append(translators, translator)
+			}
+			translation, err := NewFallback(translators...).Translate(context.Background(), "a")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Translate() error = %v, want error %t", err, test.wantErr)
+			}
+			if translation != test.want {
+				t.Errorf("Translate() = %q, want %q", translation, test.want)
+			}
+		})
+	}
+}
+
+func TestFallbackUnwrap(t *testing.T) {
+	if _, ok := AsHealthChecker(NewFallback(&translatetest.Fake{}, &DeepL{})); ok {
+		t.Error("fallback checks its health, but its first translator doesn't")
+	}
+	deepL := &DeepL{}
+	if checker, ok := AsHealthChecker(NewFallback(deepL, &translatetest.Fake{})); !ok || checker != deepL {
+		t.Errorf("AsHealthChecker() = %v, %t, want the first translator", checker, ok)
+	}
+}
@@ -2,10 +2,14 @@ package translate
 
 import (
 	"context"
+	"errors"
 	"html"
+	"net/http"
+	"strings"
 
 	"cloud.google.com/go/translate"
 	"golang.org/x/text/language"
+	"google.golang.org/api/googleapi"
 )
 
 type Google struct {
@@ -26,10 +30,16 @@ func NewGoogle(translateTo string) (*Google, error) {
 	return &Google{client, language}, nil
 }
 
-func (g *Google) Translate(source string) (string, error) {
-	translation, err := g.client.Translate(context.Background(), []string{source}, g.target, nil)
+// HealthCheck verifies the credentials with a trivial translation.
+func (g *Google) HealthCheck(ctx context.Context) error {
+	_, err := g.Translate(ctx, "ok")
+	return err
+}
+
+func (g *Google) Translate(ctx context.Context, source string) (string, error) {
+	translation, err := g.client.Translate(ctx, []string{source}, g.target, nil)
 	if err != nil {
-		return "", err
+		return "", googleError(err)
 	}
 	if len(translation) == 0 {
 		return "", nil
@@ -39,6 +49,36 @@ func (g *Google) Translate(source string) (string, error) {
 	return translatedText, nil
 }
 
+// googleError maps the Google API errors to translation errors.
+func googleError(err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return wrapNetworkError(err)
+	}
+	switch apiErr.Code {
+	case http.StatusBadRequest:
+		// Bad language pair, or an invalid value for the target, the only parameter besides the text
+		if message := strings.ToLower(apiErr.Message); strings.Contains(message, "language") || strings.Contains(message, "invalid value") {
+			return &Error{ErrUnsupportedLanguage, err}
+		}
+	case http.StatusUnauthorized:
+		return &Error{ErrAuth, err}
+	case http.StatusTooManyRequests:
+		return &Error{ErrRateLimited, err}
+	case http.StatusForbidden:
+		for _, item := range apiErr.Errors {
+			switch reason := strings.ToLower(item.Reason); {
+			case strings.Contains(reason, "ratelimit"): // rateLimitExceeded, userRateLimitExceeded
+				return &Error{ErrRateLimited, err}
+			case strings.Contains(reason, "quota"), strings.Contains(reason, "dailylimit"):
+				return &Error{ErrQuota, err}
+			}
+		}
+		return &Error{ErrAuth, err}
+	}
+	return err
+}
+
 func (g *Google) Close() {
 	_ = g.client.Close()
 }
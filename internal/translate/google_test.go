@@ -0,0 +1,44 @@
+package translate
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestGoogleError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind error // nil for the error returned as is
+	}{
+		{"unauthorized", &googleapi.Error{Code: http.StatusUnauthorized}, ErrAuth},
+		{"rate limited", &googleapi.Error{Code: http.StatusTooManyRequests}, ErrRateLimited},
+		{"user rate limit", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, ErrRateLimited},
+		{"quota", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, ErrQuota},
+		{"daily limit", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "dailyLimitExceeded"}}}, ErrQuota},
+		{"forbidden", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}, ErrAuth},
+		{"bad language pair", &googleapi.Error{Code: http.StatusBadRequest, Message: "Bad language pair: ja|xx"}, ErrUnsupportedLanguage},
+		{"invalid target", &googleapi.Error{Code: http.StatusBadRequest, Message: "Invalid Value"}, ErrUnsupportedLanguage},
+		{"bad request", &googleapi.Error{Code: http.StatusBadRequest, Message: "Required Text"}, nil},
+		{"server error", &googleapi.Error{Code: http.StatusInternalServerError}, nil},
+		{"network", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, ErrNetwork},
+		{"other", errors.New("unexpected"), nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := googleError(test.err)
+			if !errors.Is(err, test.err) {
+				t.Errorf("googleError() = %v, doesn't wrap %v", err, test.err)
+			}
+			for _, kind := range errorKinds {
+				if errors.Is(err, kind) != (kind == test.kind) {
+					t.Errorf("googleError() = %v, want kind %v", err, test.kind)
+				}
+			}
+		})
+	}
+}
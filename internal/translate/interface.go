@@ -1,6 +1,58 @@
 package translate
 
+import "context"
+
 type Translator interface {
-	Translate(toTranslate string) (string, error)
+	Translate(ctx context.Context, toTranslate string) (string, error)
 	Close()
 }
+
+// Usage is the translation quota usage, in characters.
+type Usage struct {
+	Used  int64
+	Limit int64
+}
+
+// UsageReporter is implemented by the translators able to report their quota usage.
+type UsageReporter interface {
+	Usage(ctx context.Context) (*Usage, error)
+}
+
+// HealthChecker is implemented by the translators able to verify their credentials with a minimal call.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Wrapper is implemented by the translators wrapping another one, such as a cache, so that the capabilities of the
+// wrapped translator can be reached.
+type Wrapper interface {
+	Unwrap() Translator
+}
+
+// AsUsageReporter returns the first translator of the wrapping chain able to report its quota usage.
+func AsUsageReporter(translator Translator) (UsageReporter, bool) {
+	for {
+		if reporter, ok := translator.(UsageReporter); ok {
+			return reporter, true
+		}
+		wrapper, ok := translator.(Wrapper)
+		if !ok {
+			return nil, false
+		}
+		translator = wrapper.Unwrap()
+	}
+}
+
+// AsHealthChecker returns the first translator of the wrapping chain able to check its health.
+func AsHealthChecker(translator Translator) (HealthChecker, bool) {
+	for {
+		if checker, ok := translator.(HealthChecker); ok {
+			return checker, true
+		}
+		wrapper, ok := translator.(Wrapper)
+		if !ok {
+			return nil, false
+		}
+		translator = wrapper.Unwrap()
+	}
+}
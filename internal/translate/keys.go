@@ -0,0 +1,157 @@
+package translate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// How long a key is left aside after hitting its limits
+const (
+	quotaCooldown     = 24 * time.Hour
+	rateLimitCooldown = time.Minute
+)
+
+// KeyRotation spreads the translations over several authentication keys of a provider: when a key exceeds its quota
+// or gets rate limited, it is left aside for a while and the next one is used. The cooldowns are saved to a state
+// file so that exhausted keys aren't tried again after a restart.
+type KeyRotation struct {
+	mu          sync.Mutex
+	keys        []string
+	translators []Translator
+	current     int
+	stateFile   string
+	cooldowns   map[string]time.Time // Keyed by key fingerprint, not to write the keys to disk
+}
+
+func NewKeyRotation(keys []string, connect func(key string) (Translator, error), stateFile string) (*KeyRotation, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no authentication key to rotate")
+	}
+	translators := make([]Translator, 0, len(keys))
+	for _, key := range keys {
+		translator, err := connect(key)
+		if err != nil {
+			return nil, err
+		}
+		translators = append(translators, translator)
+	}
+	k := &KeyRotation{keys: keys, translators: translators, stateFile: stateFile, cooldowns: make(map[string]time.Time)}
+	k.load()
+	return k, nil
+}
+
+func (k *KeyRotation) Translate(ctx context.Context, toTranslate string) (string, error) {
+	var lastErr error
+	for range k.keys {
+		i, translator, ok := k.available()
+		if !ok {
+			break
+		}
+		translation, err := translator.Translate(ctx, toTranslate)
+		switch {
+		case errors.Is(err, ErrQuota):
+			k.coolDown(i, quotaCooldown)
+		case errors.Is(err, ErrRateLimited):
+			k.coolDown(i, rateLimitCooldown)
+		default:
+			return translation, err
+		}
+		lastErr = err
+		log.Warn().Err(err).Msgf("authentication key %d/%d unavailable, rotating to the next one", i+1, len(k.keys))
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("all %d authentication keys are cooling down", len(k.keys))
+	}
+	return "", &Error{ErrQuota, lastErr}
+}
+
+// available returns the first key not cooling down, starting with the current one.
+func (k *KeyRotation) available() (int, Translator, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	now := time.Now()
+	for offset := range k.keys {
+		i := (k.current + offset) % len(k.keys)
+		if now.Before(k.cooldowns[fingerprint(k.keys[i])]) {
+			continue
+		}
+		k.current = i
+		return i, k.translators[i], true
+	}
+	return 0, nil, false
+}
+
+func (k *KeyRotation) coolDown(i int, cooldown time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.cooldowns[fingerprint(k.keys[i])] = time.Now().Add(cooldown)
+	k.save()
+}
+
+// Usage returns the quota usage of the current key.
+func (k *KeyRotation) Usage(ctx context.Context) (*Usage, error) {
+	k.mu.Lock()
+	translator := k.translators[k.current]
+	k.mu.Unlock()
+	reporter, ok := translator.(UsageReporter)
+	if !ok {
+		return nil, errors.New("translator doesn't report its usage")
+	}
+	return reporter.Usage(ctx)
+}
+
+// HealthCheck checks the current key, if the translator is able to.
+func (k *KeyRotation) HealthCheck(ctx context.Context) error {
+	k.mu.Lock()
+	translator := k.translators[k.current]
+	k.mu.Unlock()
+	if checker, ok := translator.(HealthChecker); ok {
+		return checker.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// load reads the cooldowns saved by a previous session, if any.
+func (k *KeyRotation) load() {
+	data, err := os.ReadFile(k.stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &k.cooldowns)
+	}
+	if err != nil {
+		log.Warn().Err(err).Msgf("unable to read the key cooldowns from %s", k.stateFile)
+	}
+}
+
+func (k *KeyRotation) save() {
+	data, err := json.MarshalIndent(k.cooldowns, "", "  ")
+	if err == nil {
+		err = os.WriteFile(k.stateFile, data, 0600)
+	}
+	if err != nil {
+		log.Warn().Err(err).Msgf("unable to save the key cooldowns to %s", k.stateFile)
+	}
+}
+
+func (k *KeyRotation) Close() {
+	for _, translator := range k.translators {
+		translator.Close()
+	}
+}
+
+// fingerprint identifies a key without revealing it.
+func fingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,83 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// LibreTranslate translates with a LibreTranslate instance, such as a self-hosted one, detecting the source language.
+type LibreTranslate struct {
+	target   string
+	endpoint string
+	apiKey   string
+}
+
+func NewLibreTranslate(translateTo, endpoint, apiKey string) (*LibreTranslate, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("no libreTranslate endpoint")
+	}
+	return &LibreTranslate{translateTo, strings.TrimSuffix(endpoint, "/"), apiKey}, nil
+}
+
+type libreTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText string `json:"translatedText"`
+	Error          string `json:"error"`
+}
+
+func (l *LibreTranslate) Translate(ctx context.Context, source string) (string, error) {
+	body, err := json.Marshal(libreTranslateRequest{Q: source, Source: "auto", Target: l.target, APIKey: l.apiKey})
+	if err != nil {
+		return "", err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, l.endpoint+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	r.Header.Add("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return "", &Error{ErrNetwork, err}
+	}
+	defer resp.Body.Close()
+
+	var response libreTranslateResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&response)
+	if resp.StatusCode != http.StatusOK {
+		return "", libreTranslateError(resp.StatusCode, response.Error)
+	}
+	if decodeErr != nil {
+		return "", decodeErr
+	}
+	return response.TranslatedText, nil
+}
+
+// libreTranslateError maps the LibreTranslate error status codes to translation errors, along with the message returned.
+func libreTranslateError(statusCode int, message string) error {
+	err := fmt.Errorf("libreTranslate returned status %d", statusCode)
+	if message != "" {
+		err = fmt.Errorf("libreTranslate returned status %d: %s", statusCode, message)
+	}
+	switch {
+	case statusCode == http.StatusBadRequest && strings.Contains(message, "not supported"): // xx is not supported
+		return &Error{ErrUnsupportedLanguage, err}
+	case statusCode == http.StatusForbidden:
+		return &Error{ErrAuth, err}
+	case statusCode == http.StatusTooManyRequests:
+		return &Error{ErrRateLimited, err}
+	}
+	return err
+}
+
+func (l *LibreTranslate) Close() {}
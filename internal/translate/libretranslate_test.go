@@ -0,0 +1,99 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestLibreTranslate returns a LibreTranslate translator sending its requests to the given handler.
+func newTestLibreTranslate(t *testing.T, handler http.HandlerFunc) *LibreTranslate {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	l, err := NewLibreTranslate("en", server.URL+"/", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestLibreTranslate(t *testing.T) {
+	l := newTestLibreTranslate(t, func(w http.ResponseWriter, r *http.Request) {
+		var request libreTranslateRequest
+		if r.URL.Path != "/translate" || json.NewDecoder(r.Body).Decode(&request) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if request != (libreTranslateRequest{Q: "こんにちは", Source: "auto", Target: "en", APIKey: "key"}) {
+			t.Errorf("unexpected request %+v", request)
+		}
+		_, _ = w.Write([]byte(`{"translatedText": "hello"}`))
+	})
+
+	translation, err := l.Translate(context.Background(), "こんにちは")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translation != "hello" {
+		t.Errorf("Translate() = %q, want %q", translation, "hello")
+	}
+}
+
+func TestLibreTranslateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		kind    error  // nil for a plain error
+		message string // Expected in the error
+	}{
+		{"invalid key", http.StatusForbidden, `{"error": "Invalid API key"}`, ErrAuth, "Invalid API key"},
+		{"slow down", http.StatusTooManyRequests, `{"error": "Slowdown: 30 per 1 minute"}`, ErrRateLimited, "Slowdown"},
+		{"unsupported", http.StatusBadRequest, `{"error": "xx is not supported"}`, ErrUnsupportedLanguage, "not supported"},
+		{"bad request", http.StatusBadRequest, `{"error": "Invalid request: missing q parameter"}`, nil, "missing q"},
+		{"error body", http.StatusInternalServerError, `{"error": "Cannot translate text"}`, nil, "Cannot translate text"},
+		{"no body", http.StatusBadGateway, ``, nil, "status 502"},
+		{"not json", http.StatusServiceUnavailable, `<html>unavailable</html>`, nil, "status 503"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newTestLibreTranslate(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			})
+
+			_, err := l.Translate(context.Background(), "a")
+			if err == nil {
+				t.Fatal("Translate() succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), test.message) {
+				t.Errorf("Translate() error = %q, want it to contain %q", err, test.message)
+			}
+			for _, kind := range errorKinds {
+				if errors.Is(err, kind) != (kind == test.kind) {
+					t.Errorf("Translate() error = %v, want kind %v", err, test.kind)
+				}
+			}
+		})
+	}
+}
+
+func TestLibreTranslateInvalidResponse(t *testing.T) {
+	l := newTestLibreTranslate(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`not json`))
+	})
+	if _, err := l.Translate(context.Background(), "a"); err == nil {
+		t.Error("Translate() succeeded on an invalid response, want an error")
+	}
+}
+
+func TestNewLibreTranslateNoEndpoint(t *testing.T) {
+	if _, err := NewLibreTranslate("en", "", ""); err == nil {
+		t.Error("NewLibreTranslate() succeeded without endpoint, want an error")
+	}
+}
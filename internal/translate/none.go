@@ -0,0 +1,18 @@
+package translate
+
+import "context"
+
+// None returns the text unchanged, to try the capture and OCR settings without a translation account.
+type None struct {
+}
+
+func NewNone() *None {
+	return &None{}
+}
+
+func (n *None) Translate(ctx context.Context, toTranslate string) (string, error) {
+	return toTranslate, nil
+}
+
+func (n *None) Close() {
+}
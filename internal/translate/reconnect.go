@@ -0,0 +1,73 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Reconnecting recreates its translator after a number of consecutive failures, as long sessions may end up with a
+// stale connection failing every call. Authentication, quota and rate limit errors don't count as failures.
+type Reconnecting struct {
+	mu         sync.Mutex
+	connect    func() (Translator, error)
+	translator Translator
+	threshold  int
+	failures   int
+}
+
+func NewReconnecting(connect func() (Translator, error), threshold int) (*Reconnecting, error) {
+	translator, err := connect()
+	if err != nil {
+		return nil, err
+	}
+	return &Reconnecting{connect: connect, translator: translator, threshold: threshold}, nil
+}
+
+func (r *Reconnecting) Translate(ctx context.Context, toTranslate string) (string, error) {
+	r.mu.Lock()
+	translator := r.translator
+	r.mu.Unlock()
+
+	translation, err := translator.Translate(ctx, toTranslate)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case err == nil:
+		r.failures = 0
+	case errors.Is(err, ErrAuth), errors.Is(err, ErrQuota), errors.Is(err, ErrRateLimited),
+		errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+	default:
+		r.failures++
+		if r.failures >= r.threshold && translator == r.translator {
+			r.reconnect()
+		}
+	}
+	return translation, err
+}
+
+// Unwrap returns the current translator, for its capabilities to be reached.
+func (r *Reconnecting) Unwrap() Translator {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.translator
+}
+
+func (r *Reconnecting) reconnect() {
+	log.Warn().Msgf("translator failed %d times in a row, reconnecting", r.failures)
+	translator, err := r.connect()
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to reconnect translator, keeping the current one")
+		return
+	}
+	r.translator.Close()
+	r.translator, r.failures = translator, 0
+}
+
+func (r *Reconnecting) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.translator.Close()
+}
@@ -0,0 +1,46 @@
+package translate
+
+import (
+	"context"
+	"sync"
+)
+
+// Switch translates with one of several translators, such as one per target language, the one used being selected at
+// runtime.
+type Switch struct {
+	mu          sync.Mutex
+	translators []Translator
+	current     int
+}
+
+func NewSwitch(translators ...Translator) *Switch {
+	return &Switch{translators: translators}
+}
+
+// Select makes the translator of the given index the one used for the next translations.
+func (s *Switch) Select(i int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = i
+}
+
+func (s *Switch) selected() Translator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.translators[s.current]
+}
+
+func (s *Switch) Translate(ctx context.Context, toTranslate string) (string, error) {
+	return s.selected().Translate(ctx, toTranslate)
+}
+
+// Unwrap returns the selected translator.
+func (s *Switch) Unwrap() Translator {
+	return s.selected()
+}
+
+func (s *Switch) Close() {
+	for _, translator := range s.translators {
+		translator.Close()
+	}
+}
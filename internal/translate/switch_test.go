@@ -0,0 +1,47 @@
+package translate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bquenin/interpreter/internal/translate/translatetest"
+)
+
+func TestSwitch(t *testing.T) {
+	english, french := &translatetest.Fake{Prefix: "en:"}, &translatetest.Fake{Prefix: "fr:"}
+	s := NewSwitch(english, french)
+	tests := []struct {
+		selected   int
+		want       string
+		translator Translator
+	}{
+		{0, "en:a", english},
+		{1, "fr:a", french},
+		{0, "en:a", english},
+	}
+	for _, test := range tests {
+		s.Select(test.selected)
+		translation, err := s.Translate(context.Background(), "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if translation != test.want {
+			t.Errorf("Translate() with translator %d selected = %q, want %q", test.selected, translation, test.want)
+		}
+		if s.Unwrap() != test.translator {
+			t.Errorf("Unwrap() with translator %d selected returned another translator", test.selected)
+		}
+	}
+}
+
+func TestSwitchUnwrap(t *testing.T) {
+	deepL := &DeepL{}
+	s := NewSwitch(&translatetest.Fake{}, deepL)
+	if _, ok := AsUsageReporter(s); ok {
+		t.Error("switch reports its usage, but the selected translator doesn't")
+	}
+	s.Select(1)
+	if reporter, ok := AsUsageReporter(s); !ok || reporter != deepL {
+		t.Errorf("AsUsageReporter() = %v, %t, want the selected translator", reporter, ok)
+	}
+}
@@ -0,0 +1,63 @@
+// Package translatetest provides a fake translator for the tests of the translation pipeline.
+package translatetest
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake is a translator for the tests, translating a text by prefixing it and recording the texts it is asked to
+// translate.
+type Fake struct {
+	Prefix  string
+	Err     error         // Returned instead of a translation when set
+	Release chan struct{} // When set, every translation waits for the channel to be closed or to receive a value
+
+	mu          sync.Mutex
+	calls       []string
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *Fake) Translate(ctx context.Context, toTranslate string) (string, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, toTranslate)
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	if f.Release != nil {
+		select {
+		case <-f.Release:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if f.Err != nil {
+		return "", f.Err
+	}
+	return f.Prefix + toTranslate, nil
+}
+
+// Calls returns the texts the translator was asked to translate, in order.
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// MaxInFlight returns the maximum number of translations that were running at the same time.
+func (f *Fake) MaxInFlight() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxInFlight
+}
+
+func (f *Fake) Close() {}